@@ -2,32 +2,39 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/ast"
 	"go/format"
-	"go/parser"
 	"go/token"
+	"go/types"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+
+	"golang.org/x/tools/go/packages"
 )
 
 var (
-	typeName = flag.String("type", "", "permission type")
-	fileName = flag.String("file", "", "pb.go file to parse")
-	output   = flag.String("output", "", "output file name; default srcdir/<type>_string.go")
+	typeName   = flag.String("type", "", "permission type")
+	pkgPath    = flag.String("pkg", "github.com/subiz/header/common", "import path of the package defining the permission type")
+	output     = flag.String("output", "", "output file name; default srcdir/<type>_checker.go")
+	testOutput = flag.String("test-output", "", "output test file name; default srcdir/<type>_checker_test.go")
+	actionFile = flag.String("actions", "", "file declaring the actions beyond create, read, update and delete")
+	tsOutput   = flag.String("ts", "", "if set, write a TypeScript module with the resources, Base and predefined roles to this file instead of the checkers, Base and the roles are read from the output of perm json on stdin")
 )
 
 // Usage is a replacement usage function for the flags package.
 func Usage() {
-	fmt.Fprintf(os.Stderr, "Usage of stringer:\n")
-	fmt.Fprintf(os.Stderr, "\tpermgen [flags] -type T [directory]\n")
-	fmt.Fprintf(os.Stderr, "\tpermgen [flags] -type T files... # Must be a single package\n")
-	fmt.Fprintf(os.Stderr, "For more information, see:\n")
-	fmt.Fprintf(os.Stderr, "\thttp://godoc.org/golang.org/x/tools/cmd/stringer\n")
+	fmt.Fprintf(os.Stderr, "Usage of permgen:\n")
+	fmt.Fprintf(os.Stderr, "\tpermgen [flags] -type T\n")
 	fmt.Fprintf(os.Stderr, "Flags:\n")
 	flag.PrintDefaults()
 }
@@ -42,19 +49,38 @@ func main() {
 		os.Exit(2)
 	}
 
-	if len(*fileName) == 0 {
+	if len(*pkgPath) == 0 {
 		flag.Usage()
 		os.Exit(2)
 	}
 
-	// We accept either one directory or a list of files. Which do we have?
-	args := flag.Args()
-	if len(args) == 0 {
-		// Default: process whole package in current directory.
-		args = []string{"."}
+	fields, err := loadFields(*pkgPath, *typeName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	actions := make([]Action, 0)
+	if *actionFile != "" {
+		if actions, err = loadActions(*actionFile, fields); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *tsOutput != "" {
+		defs, err := readDefinitions(os.Stdin)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ts := Generator{}
+		ts.Printf("// Code generated by \"perm_generator %s\"; DO NOT EDIT.\n", strings.Join(os.Args[1:], " "))
+		ts.buildTypeScript(fields, actions, defs)
+		if err := ioutil.WriteFile(*tsOutput, ts.buf.Bytes(), 0644); err != nil {
+			log.Fatalf("writing typescript output: %s", err)
+		}
+		return
 	}
 
-	// Parse the package once.
 	g := Generator{}
 
 	// Print the header and package clause.
@@ -74,15 +100,10 @@ func contains(s string, ss []string) bool {
 }
 
 `)
-	src, err := ioutil.ReadFile(*fileName)
-	if err != nil {
-		log.Fatalf("reading input: %s", err)
-	}
-	// Run generate for each type.
-	g.generate(string(src), *typeName)
+	g.generate(fields, actions, *typeName)
 
 	// Format the output.
-	src = g.format()
+	src := g.format()
 
 	// Write to file.
 	outputName := *output
@@ -93,8 +114,44 @@ func contains(s string, ss []string) bool {
 	if err := ioutil.WriteFile(outputName, src, 0644); err != nil {
 		log.Fatalf("writing output: %s", err)
 	}
+
+	// Generate the test suite for the checkers.
+	tg := Generator{}
+	tg.Printf("// Code generated by \"perm_generator %s\"; DO NOT EDIT.\n", strings.Join(os.Args[1:], " "))
+	tg.buildTests(fields, actions, *typeName)
+
+	testOutputName := *testOutput
+	if testOutputName == "" {
+		baseName := fmt.Sprintf("%s_checker_test.go", *typeName)
+		testOutputName = filepath.Join(".", strings.ToLower(baseName))
+	}
+	if err := ioutil.WriteFile(testOutputName, tg.format(), 0644); err != nil {
+		log.Fatalf("writing test output: %s", err)
+	}
+}
+
+// Definitions is the part of the output of perm json the TypeScript module
+// is built from, each permission maps the snake case name of a field to its
+// value, fields without any permission are left out
+type Definitions struct {
+	Base  map[string]int32            `json:"base"`
+	Roles map[string]map[string]int32 `json:"roles"`
+}
+
+// readDefinitions decodes the output of perm json
+func readDefinitions(r io.Reader) (*Definitions, error) {
+	defs := &Definitions{}
+	if err := json.NewDecoder(r).Decode(defs); err != nil {
+		return nil, fmt.Errorf("reading definitions: %s", err)
+	}
+
+	if len(defs.Base) == 0 {
+		return nil, fmt.Errorf("reading definitions: base is missing, expect the output of perm json")
+	}
+	return defs, nil
 }
 
+
 // Generator holds the state of the analysis. Primarily used to buffer
 // the output for format.Source.
 type Generator struct {
@@ -105,58 +162,218 @@ func (g *Generator) Printf(format string, args ...interface{}) {
 	fmt.Fprintf(&g.buf, format, args...)
 }
 
-// generate produces the String method for the named type.
-func (g *Generator) generate(src, typeName string) {
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, "", src, 0)
+// Field is a permission field of the permission type
+type Field struct {
+	Name      string // Go name, e.g. WhitelistIp
+	ProtoName string // protobuf name, e.g. whitelist_ip
+	Number    int    // protobuf field number
+}
+
+// loadFields loads the package at pkgPath using module-aware package loading
+// and returns the permission fields of the struct typeName
+func loadFields(pkgPath, typeName string) ([]Field, error) {
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax}
+	pkgs, err := packages.Load(cfg, pkgPath)
 	if err != nil {
-		log.Fatalf("parsing file: %s", err)
+		return nil, fmt.Errorf("loading package %s: %s", pkgPath, err)
 	}
 
-	fieldNames := make([]string, 0, 100)
-	// Set the state for this run of the walker.
-	for _, itypeDecl := range file.Decls {
-		typeDecl, ok := itypeDecl.(*ast.GenDecl)
-		if !ok {
-			continue
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("loading package %s: package contains errors", pkgPath)
+	}
+
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("loading package %s: expect 1 package, got %d", pkgPath, len(pkgs))
+	}
+
+	var structType *ast.StructType
+	getters := make(map[string]bool)
+	for _, file := range pkgs[0].Syntax {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				if decl.Tok != token.TYPE {
+					continue
+				}
+
+				// a declaration may group several types: type ( A struct{}; B struct{} )
+				for _, spec := range decl.Specs {
+					typeSpec := spec.(*ast.TypeSpec)
+					if typeSpec.Name.Name != typeName {
+						continue
+					}
+
+					st, ok := typeSpec.Type.(*ast.StructType)
+					if !ok {
+						return nil, fmt.Errorf("%s.%s is not a struct", pkgPath, typeName)
+					}
+					structType = st
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil && receiverName(decl.Recv) == typeName {
+					getters[decl.Name.Name] = true
+				}
+			}
 		}
+	}
 
-		// filter out type
-		structDecl, ok := typeDecl.Specs[0].(*ast.TypeSpec)
-		if !ok {
-			continue
+	if structType == nil {
+		return nil, fmt.Errorf("type %s is not defined in package %s", typeName, pkgPath)
+	}
+
+	fields := make([]Field, 0, len(structType.Fields.List))
+	for _, field := range structType.Fields.List {
+		// the Check functions read a field through its getter, which an
+		// embedded field doesn't have
+		if len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s.%s embeds %s, embedded fields are not supported", pkgPath, typeName, types.ExprString(field.Type))
 		}
 
-		if structDecl.Name.Name != typeName {
+		// a field may declare several names: A, B int32
+		for _, nameIdent := range field.Names {
+			name := nameIdent.Name
+			if !ast.IsExported(name) || strings.HasPrefix(name, "XXX") {
+				continue
+			}
+
+			if ident, ok := field.Type.(*ast.Ident); !ok || ident.Name != "int32" {
+				return nil, fmt.Errorf("%s.%s.%s must be int32", pkgPath, typeName, name)
+			}
+
+			if !getters["Get"+name] {
+				return nil, fmt.Errorf("%s.%s has no getter Get%s", pkgPath, typeName, name)
+			}
+
+			protoName, number := protoTag(field.Tag)
+			if protoName == "" || number <= 0 {
+				return nil, fmt.Errorf("%s.%s.%s has no protobuf name or field number in its tag", pkgPath, typeName, name)
+			}
+			fields = append(fields, Field{Name: name, ProtoName: protoName, Number: number})
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields defined for type %s.%s", pkgPath, typeName)
+	}
+	return fields, nil
+}
+
+// protoTag returns the name= part and the field number of the protobuf
+// struct tag, e.g.:
+// `protobuf:"varint,2,opt,name=account,proto3"` => account, 2
+func protoTag(tag *ast.BasicLit) (string, int) {
+	if tag == nil {
+		return "", 0
+	}
+
+	unquoted, err := strconv.Unquote(tag.Value)
+	if err != nil {
+		return "", 0
+	}
+
+	parts := strings.Split(reflect.StructTag(unquoted).Get("protobuf"), ",")
+	name, number := "", 0
+	if len(parts) > 1 {
+		number, _ = strconv.Atoi(parts[1])
+	}
+	for _, part := range parts {
+		if strings.HasPrefix(part, "name=") {
+			name = strings.TrimPrefix(part, "name=")
+		}
+	}
+	return name, number
+}
+
+// receiverName returns the name of the receiver type, without the pointer
+func receiverName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+
+	ident, ok := typ.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+	return ident.Name
+}
+
+// Action is an action beyond create, read, update and delete
+type Action struct {
+	Name      string          // e.g. Export, generates CheckExport<Resource>
+	Letter    string          // letter in permission strings, e.g. e in "u:-r--e"
+	Resources map[string]bool // fields supporting the action
+}
+
+// loadActions parses the action declaration file. Each non-empty line
+// declares an action, its letter and the resources supporting it:
+//   export e User Conversation
+// The n-th declared action uses the n-th extra bit of every level, so new
+// actions must only be appended
+func loadActions(fileName string, fields []Field) ([]Action, error) {
+	src, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("reading actions: %s", err)
+	}
+
+	known := make(map[string]bool)
+	for _, field := range fields {
+		known[field.Name] = true
+	}
+
+	actions := make([]Action, 0)
+	letters := "crud-"
+	for i, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
 
-		fields := structDecl.Type.(*ast.StructType).Fields.List
-		for _, field := range fields {
-			nameExpr := field.Names[0]
+		parts := strings.Fields(line)
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("%s:%d: expect <action> <letter> <resource>...", fileName, i+1)
+		}
 
-			start := nameExpr.Pos() - 1
-			end := nameExpr.End() - 1
-			// grab it in source
-			fieldName := src[start:end]
+		name, letter := parts[0], parts[1]
+		if strings.Trim(name, "abcdefghijklmnopqrstuvwxyz") != "" {
+			return nil, fmt.Errorf("%s:%d: action %q must only contain lower case letters", fileName, i+1, name)
+		}
 
-			if !ast.IsExported(fieldName) {
-				continue
-			}
+		if len(letter) != 1 || letter[0] < 'a' || letter[0] > 'z' || strings.Contains(letters, letter) {
+			return nil, fmt.Errorf("%s:%d: letter %q must be a single unused lower case letter", fileName, i+1, letter)
+		}
+		letters += letter
 
-			if strings.Contains(fieldName, "XXX") {
-				continue
+		action := Action{Name: strings.Title(name), Letter: letter, Resources: make(map[string]bool)}
+		for _, resource := range parts[2:] {
+			if !known[resource] {
+				return nil, fmt.Errorf("%s:%d: unknown resource %q", fileName, i+1, resource)
 			}
-			fieldNames = append(fieldNames, fieldName)
+			action.Resources[resource] = true
 		}
-		break
+		actions = append(actions, action)
 	}
 
-	if len(fieldNames) == 0 {
-		log.Fatalf("no fields defined for type %s", typeName)
+	if len(actions) > 4 {
+		return nil, fmt.Errorf("%s: at most 4 extra actions fit in a level, got %d", fileName, len(actions))
+	}
+	return actions, nil
+}
+
+// generate produces the checker functions for the fields of the named type.
+func (g *Generator) generate(fields []Field, actions []Action, typeName string) {
+	fieldNames := make([]string, 0, len(fields))
+	for _, field := range fields {
+		fieldNames = append(fieldNames, field.Name)
 	}
 	g.buildMultipleRuns(fieldNames, typeName)
+	g.buildExtraActions(fieldNames, actions, typeName)
 	g.buildIntersectPermission(fieldNames, typeName)
+	g.buildResources(fields, typeName)
 }
 
 // format returns the gofmt-ed contents of the Generator's buffer.
@@ -178,42 +395,69 @@ func (g *Generator) buildMultipleRuns(fieldNames []string, typeName string) {
 
 	g.Printf("\n")
 	g.Printf(`
-	var CREATEPERM = strPermToInt("c")
-	var READPERM = strPermToInt("r")
-	var UPDATEPERM = strPermToInt("u")
-	var DELETEPERM = strPermToInt("d")
-
+	// the permission bits of the actions, see strPermToInt
+	const (
+		CREATEPERM int32 = 0x8
+		READPERM   int32 = 0x4
+		UPDATEPERM int32 = 0x2
+		DELETEPERM int32 = 0x1
+	)
 `)
 
 	for _, name := range fieldNames {
-		g.Printf(`func CheckCreate%s(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().Get%s()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+		for _, action := range []string{"Create", "Read", "Update", "Delete"} {
+			g.buildCheck(action, name)
+		}
+	}
 }
 
-func CheckRead%s(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().Get%s()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+// buildCheck generates Check<action><name> and its Target variant
+func (g *Generator) buildCheck(action, name string) {
+	g.Printf(`
+func Check%s%s(cred *common.Credential, accid string, agids ...string) error {
+	return checkTarget("%s", %sPERM, int64(uint32(cred.GetPerm().Get%s())), cred, Target{AccountId: accid, Owners: agids})
 }
 
-func CheckUpdate%s(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().Get%s()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+// Check%s%sTarget is Check%s%s for a resource which may be owned by agent
+// groups or guarded by conditions
+func Check%s%sTarget(cred *common.Credential, t Target) error {
+	return checkTarget("%s", %sPERM, int64(uint32(cred.GetPerm().Get%s())), cred, t)
 }
-
-func CheckDelete%s(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().Get%s()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+`, action, name, name, strings.ToUpper(action), name,
+		action, name, action, name,
+		action, name, name, strings.ToUpper(action), name)
 }
-`, name, name, name, name, name, name, name, name)
+
+// buildExtraActions generates the permission constants of the declared
+// actions and their Check functions, only for the resources supporting them
+func (g *Generator) buildExtraActions(fieldNames []string, actions []Action, typeName string) {
+	letters, names := "", ""
+	for _, action := range actions {
+		letters += action.Letter
+		names += strconv.Quote(strings.ToLower(action.Name)) + ", "
+	}
+
+	g.Printf(`
+	// extraActions lists the letters of the actions beyond create, read,
+	// update and delete, the n-th letter uses the n-th bit above crud
+	const extraActions = "%s"
+
+	// extraActionNames lists the names of the extra actions, in the order
+	// of extraActions
+	var extraActionNames = []string{%s}
+
+`, letters, strings.TrimSuffix(names, ", "))
+
+	for i, action := range actions {
+		g.Printf("const %sPERM int32 = %#x\n", strings.ToUpper(action.Name), 0x10<<uint(i))
+	}
+
+	for _, name := range fieldNames {
+		for _, action := range actions {
+			if action.Resources[name] {
+				g.buildCheck(action.Name, name)
+			}
+		}
 	}
 }
 
@@ -243,3 +487,212 @@ func (g *Generator) buildIntersectPermission(fieldNames []string, typeName strin
 		}
 	}`, fields)
 }
+
+// buildResources generates the Resources table, so runtime code can iterate
+// over permission fields without reflection
+func (g *Generator) buildResources(fields []Field, typeName string) {
+	rows := ""
+	for _, field := range fields {
+		rows += fmt.Sprintf(`{
+		Name:      "%s",
+		SnakeName: "%s",
+		Number:    %d,
+		Get:       (*common.%s).Get%s,
+		Set:       func(p *common.%s, v int32) { p.%s = v },
+	},
+`, field.Name, field.ProtoName, field.Number, typeName, field.Name, typeName, field.Name)
+	}
+
+	g.Printf(`
+
+	// Resources describes every field of common.%s
+	var Resources = []Resource{
+		%s
+	}
+`, typeName, rows)
+}
+
+// buildTests generates a test which runs every Check function generated by
+// buildMultipleRuns against the super, account, own-resource and
+// cross-account cases
+func (g *Generator) buildTests(fields []Field, actions []Action, typeName string) {
+	checks := ""
+	for _, field := range fields {
+		names := []string{"Create", "Read", "Update", "Delete"}
+		for _, action := range actions {
+			if action.Resources[field.Name] {
+				names = append(names, action.Name)
+			}
+		}
+
+		for _, action := range names {
+			checks += fmt.Sprintf(`{"Check%s%s", %sPERM, Check%s%s, Check%s%sTarget, func(p *common.%s, v int32) { p.%s = v }},`+"\n",
+				action, field.Name, strings.ToUpper(action), action, field.Name, action, field.Name, typeName, field.Name)
+		}
+	}
+
+	g.Printf(`
+package perm
+
+import (
+	"testing"
+
+	"github.com/subiz/header/common"
+)
+
+func TestGeneratedCheckers(t *testing.T) {
+	checks := []struct {
+		name     string
+		required int32
+		check    func(cred *common.Credential, accid string, agids ...string) error
+		target   func(cred *common.Credential, t Target) error
+		set      func(p *common.%s, v int32)
+	}{
+		%s
+	}
+
+	for _, c := range checks {
+		// every action except the required one, on every level
+		others := int32(0)
+		for _, level := range []string{"u", "g", "a", "s"} {
+			others |= makePerm(level, 0xFF&^c.required)
+		}
+
+		tcs := []struct {
+			desc   string
+			accid  string
+			issuer string
+			groups []string
+			perm   int32
+			pass   bool
+		}{
+			{"super accept", "acx", "agx", nil, makePerm("s", c.required), true},
+			{"super reject", "acx", "agx", nil, others, false},
+			{"account accept", "ac1", "ag2", nil, makePerm("a", c.required), true},
+			{"account reject", "ac1", "ag2", nil, others, false},
+			{"own accept", "ac1", "ag1", nil, makePerm("u", c.required), true},
+			{"own reject not owner", "ac1", "ag2", nil, makePerm("u", c.required), false},
+			{"own reject", "ac1", "ag1", nil, others, false},
+			{"group reject no group", "ac1", "ag2", nil, makePerm("g", c.required), false},
+			{"group accept", "ac1", "ag2", []string{"gr1"}, makePerm("g", c.required), true},
+			{"group reject not member", "ac1", "ag2", []string{"gr2"}, makePerm("g", c.required), false},
+			{"group reject", "ac1", "ag2", []string{"gr1"}, others, false},
+			{"cross account reject", "acx", "ag1", []string{"gr1"}, makePerm("u", c.required) | makePerm("g", c.required) | makePerm("a", c.required), false},
+		}
+
+		for _, tc := range tcs {
+			p := &common.%s{}
+			c.set(p, tc.perm)
+			cred := &common.Credential{AccountId: tc.accid, Issuer: tc.issuer, Perm: p}
+			err := c.target(cred, Target{AccountId: "ac1", Owners: []string{"ag1"}, Groups: []string{"gr1"}, CallerGroups: tc.groups})
+			if err == nil != tc.pass {
+				t.Errorf("[%%s %%s] expect pass: %%v, but got err %%v", c.name, tc.desc, tc.pass, err)
+			}
+
+			// without group information both variants must agree
+			if tc.groups == nil {
+				if err := c.check(cred, "ac1", "ag1"); err == nil != tc.pass {
+					t.Errorf("[%%s %%s] expect pass: %%v, but got err %%v", c.name, tc.desc, tc.pass, err)
+				}
+			}
+		}
+
+		if err := c.check(nil, "ac1", "ag1"); err == nil {
+			t.Errorf("[%%s nil] expect err", c.name)
+		}
+	}
+}
+`, typeName, checks, typeName)
+}
+
+// buildTypeScript generates a TypeScript module mirroring the permission
+// layout, so front-end code checks permissions the same way as checkPerm
+func (g *Generator) buildTypeScript(fields []Field, actions []Action, defs *Definitions) {
+	g.Printf(`
+// Resource is a field of the permission
+export enum Resource {
+`)
+	for _, field := range fields {
+		g.Printf("\t%s = '%s',\n", field.Name, field.ProtoName)
+	}
+	g.Printf(`}
+
+// Perm is a permission in V1 encoding, or in V2 encoding as a bigint since
+// V2 keeps its version in the top byte of an int64
+export type Perm = number | bigint
+
+export type Permission = { [R in Resource]?: number }
+
+export const CREATEPERM = 8
+export const READPERM = 4
+export const UPDATEPERM = 2
+export const DELETEPERM = 1
+`)
+	for i, action := range actions {
+		g.Printf("export const %sPERM = 0x%x\n", strings.ToUpper(action.Name), 0x10<<uint(i))
+	}
+
+	g.Printf(`
+// getPerm returns the permission of level u (own resources), g (agent group),
+// a (account) or s (super), the upper 4 bits are the extra actions of the level
+export function getPerm(level: 'u' | 'g' | 'a' | 's', num: Perm): number {
+	if (typeof num === 'bigint') {
+		if (num >> BigInt(56) !== BigInt(2)) return getPerm(level, Number(BigInt.asIntN(32, num)))
+		const i = ['u', 'a', 's', 'g'].indexOf(level)
+		return i < 0 ? 0 : Number((num >> BigInt(8 * i)) & BigInt(0xff))
+	}
+
+	const shifts = { u: [0, 12], a: [4, 16], s: [8, 20], g: [24, 28] }
+	const shift = shifts[level]
+	if (!shift) return 0
+	return ((num >> shift[0]) & 0xf) | (((num >> shift[1]) & 0xf) << 4)
+}
+
+// checkPerm mirrors core.CheckPerm in the Go package. Conditions attached to
+// actions are registered by the server at runtime and are not known here: a
+// true result means the permission allows the action, the server may still
+// deny it when a condition doesn't hold
+export function checkPerm(required: number, callerperm: Perm, ismine: boolean, ingroup: boolean, sameaccount: boolean): boolean {
+	if ((required & getPerm('s', callerperm)) === required) return true
+	if (!sameaccount) return false
+	if (ismine && (required & getPerm('u', callerperm)) === required) return true
+	if (ingroup && (required & getPerm('g', callerperm)) === required) return true
+	return (required & getPerm('a', callerperm)) === required
+}
+
+// check reports whether perm allows the required action on resource, see
+// checkPerm
+export function check(perm: Permission | undefined, resource: Resource, required: number, ismine: boolean, ingroup: boolean, sameaccount: boolean): boolean {
+	return checkPerm(required, (perm && perm[resource]) || 0, ismine, ingroup, sameaccount)
+}
+`)
+
+	g.Printf("\n// Base is the biggest possible permission that is valid\n")
+	g.Printf("export const Base: Permission = %s\n", tsPermission(fields, defs.Base))
+
+	names := make([]string, 0, len(defs.Roles))
+	for name := range defs.Roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.Printf("\n// Roles are the predefined roles\n")
+	g.Printf("export const Roles: { [name: string]: Permission } = {\n")
+	for _, name := range names {
+		g.Printf("\t%s: %s,\n", name, strings.Replace(tsPermission(fields, defs.Roles[name]), "\n", "\n\t", -1))
+	}
+	g.Printf("}\n")
+}
+
+// tsPermission formats a permission as a TypeScript object literal. Values
+// are printed as uint32, JavaScript bitwise operators convert them back to
+// int32
+func tsPermission(fields []Field, perm map[string]int32) string {
+	out := "{\n"
+	for _, field := range fields {
+		if num, ok := perm[field.ProtoName]; ok {
+			out += fmt.Sprintf("\t[Resource.%s]: 0x%03x,\n", field.Name, uint32(num))
+		}
+	}
+	return out + "}"
+}
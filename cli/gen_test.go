@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm"
+)
+
+var update = flag.Bool("update", false, "rewrite ../permission.ts")
+
+func load(t *testing.T) ([]Field, []Action, *Definitions) {
+	fields, err := loadFields("github.com/subiz/header/common", "Permission")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actions, err := loadActions("../actions.txt", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// what perm json prints
+	base := perm.MakeBase()
+	defs := &Definitions{Base: permissionMap(&base), Roles: map[string]map[string]int32{}}
+	for _, name := range perm.RoleNames() {
+		role, _ := perm.Role(name)
+		defs.Roles[name] = permissionMap(role)
+	}
+	return fields, actions, defs
+}
+
+func permissionMap(p *common.Permission) map[string]int32 {
+	m := make(map[string]int32)
+	for _, r := range perm.Resources {
+		if num := r.Get(p); num != 0 {
+			m[r.SnakeName] = num
+		}
+	}
+	return m
+}
+
+// permission.ts is the golden file, run go test -update after changing the
+// template
+func TestTypeScript(t *testing.T) {
+	fields, actions, defs := load(t)
+	g := Generator{}
+	g.buildTypeScript(fields, actions, defs)
+
+	golden, err := ioutil.ReadFile("../permission.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first line records the flags of the generator
+	header := golden[:bytes.IndexByte(golden, '\n')+1]
+	if *update {
+		if err := ioutil.WriteFile("../permission.ts", append(header, g.buf.Bytes()...), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	if !bytes.Equal(golden[len(header):], g.buf.Bytes()) {
+		t.Error("permission.ts is stale, run go generate")
+	}
+
+	if !strings.Contains(g.buf.String(), "[Resource.Conversation]: 0x") || strings.Contains(g.buf.String(), "0x-") {
+		t.Error("expect permissions formatted as unsigned hex")
+	}
+}
+
+func TestTSPermission(t *testing.T) {
+	fields := []Field{{Name: "Conversation", ProtoName: "conversation"}}
+	got := tsPermission(fields, map[string]int32{"conversation": -0x10000000})
+	if got != "{\n\t[Resource.Conversation]: 0xf0000000,\n}" {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestReadDefinitions(t *testing.T) {
+	defs, err := readDefinitions(strings.NewReader(`{"resources": [], "base": {"conversation": 1911}, "roles": {"agent": {"conversation": 6}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if defs.Base["conversation"] != 1911 || defs.Roles["agent"]["conversation"] != 6 {
+		t.Errorf("unexpected %+v", defs)
+	}
+
+	for _, in := range []string{"", "{", `{"roles": {}}`} {
+		if _, err := readDefinitions(strings.NewReader(in)); err == nil {
+			t.Errorf("%q: expect error", in)
+		}
+	}
+}
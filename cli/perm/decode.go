@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/subiz/perm"
+)
+
+func runDecode(args []string) {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm decode:\n")
+		fmt.Fprintf(os.Stderr, "\tperm decode <hex or base64 blob>\n")
+		fmt.Fprintf(os.Stderr, "\tperm decode - # read the blob from stdin\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	blob := fs.Arg(0)
+	if blob == "-" {
+		var err error
+		if blob, err = readInput(blob); err != nil {
+			log.Fatalf("reading input: %s", err)
+		}
+	}
+
+	p, err := parsePermission(blob)
+	if err != nil {
+		log.Fatalf("decoding permission: %s", err)
+	}
+	fmt.Print(perm.FormatPermission(p))
+}
+
+func runEncode(args []string) {
+	fs := flag.NewFlagSet("encode", flag.ExitOnError)
+	format := fs.String("format", "hex", "output format, hex or base64")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm encode:\n")
+		fmt.Fprintf(os.Stderr, "\tperm encode [flags] <file>\n")
+		fmt.Fprintf(os.Stderr, "\tperm encode [flags] - # read from stdin\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	src, err := readInput(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("reading input: %s", err)
+	}
+
+	p, err := perm.ParsePermission(src)
+	if err != nil {
+		log.Fatalf("parsing permission: %s", err)
+	}
+
+	b, err := proto.Marshal(p)
+	if err != nil {
+		log.Fatalf("encoding permission: %s", err)
+	}
+
+	switch *format {
+	case "hex":
+		fmt.Println(hex.EncodeToString(b))
+	case "base64":
+		fmt.Println(base64.StdEncoding.EncodeToString(b))
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+}
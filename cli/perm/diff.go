@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm"
+)
+
+func runDiff(args []string) {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm diff:\n")
+		fmt.Fprintf(os.Stderr, "\tperm diff <left> <right>\n")
+		fmt.Fprintf(os.Stderr, "Each side is either a role name (%s, base), a file in human-readable\n", strings.Join(perm.RoleNames(), ", "))
+		fmt.Fprintf(os.Stderr, "format, or a hex or base64 encoded protobuf blob.\n")
+		fmt.Fprintf(os.Stderr, "Exit status is 1 when the right side grants more than the left side, 2 on errors.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	left, err := loadPermission(fs.Arg(0))
+	if err != nil {
+		log.Printf("loading %s: %s", fs.Arg(0), err)
+		os.Exit(2)
+	}
+
+	right, err := loadPermission(fs.Arg(1))
+	if err != nil {
+		log.Printf("loading %s: %s", fs.Arg(1), err)
+		os.Exit(2)
+	}
+
+	changes := perm.Diff(left, right)
+	for _, c := range changes {
+		fmt.Println(c)
+	}
+
+	if perm.Escalates(changes) {
+		os.Exit(1)
+	}
+}
+
+// loadPermission resolves spec to a permission, spec is either a predefined
+// role name, a file or a protobuf blob
+func loadPermission(spec string) (*common.Permission, error) {
+	if spec == "base" {
+		base := perm.MakeBase()
+		return &base, nil
+	}
+
+	if role, ok := perm.Role(spec); ok {
+		return role, nil
+	}
+
+	if _, err := os.Stat(spec); err == nil {
+		src, err := readInput(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		if p, err := perm.ParsePermission(src); err == nil {
+			return p, nil
+		}
+		return parsePermission(src)
+	}
+	return parsePermission(spec)
+}
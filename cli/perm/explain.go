@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm"
+)
+
+func runExplain(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	credStr := fs.String("cred", "", "credential, either in JSON or hex or base64 encoded protobuf")
+	permStr := fs.String("perm", "", "hex or base64 encoded protobuf permission, overrides the credential's permission")
+	credAcc := fs.String("cred-account", "", "account id of the credential, overrides the credential's account id")
+	issuer := fs.String("issuer", "", "issuer of the credential, overrides the credential's issuer")
+	accid := fs.String("account", "", "account id of the resource")
+	owners := fs.String("owners", "", "comma separated agent ids which own the resource")
+	groups := fs.String("groups", "", "comma separated agent group ids which own the resource")
+	callerGroups := fs.String("caller-groups", "", "comma separated agent group ids the caller is a member of")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm explain:\n")
+		fmt.Fprintf(os.Stderr, "\tperm explain [flags] <resource> <action>\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cred, err := parseCredential(*credStr)
+	if err != nil {
+		log.Fatalf("parsing credential: %s", err)
+	}
+
+	if *permStr != "" {
+		cred.Perm, err = parsePermission(*permStr)
+		if err != nil {
+			log.Fatalf("parsing permission: %s", err)
+		}
+	}
+
+	if *credAcc != "" {
+		cred.AccountId = *credAcc
+	}
+
+	if *issuer != "" {
+		cred.Issuer = *issuer
+	}
+
+	e, err := perm.ExplainTarget(fs.Arg(0), fs.Arg(1), cred, perm.Target{
+		AccountId:    *accid,
+		Owners:       splitList(*owners),
+		Groups:       splitList(*groups),
+		CallerGroups: splitList(*callerGroups),
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(e)
+	if !e.Allowed {
+		os.Exit(1)
+	}
+}
+
+func parseCredential(s string) (*common.Credential, error) {
+	cred := &common.Credential{}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return cred, nil
+	}
+
+	if strings.HasPrefix(s, "{") {
+		return cred, jsonpb.UnmarshalString(s, cred)
+	}
+
+	b, err := decodeBlob(s)
+	if err != nil {
+		return nil, err
+	}
+	return cred, proto.Unmarshal(b, cred)
+}
+
+func parsePermission(s string) (*common.Permission, error) {
+	b, err := decodeBlob(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &common.Permission{}
+	return p, proto.Unmarshal(b, p)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm"
+)
+
+// Definitions is the JSON document printed by perm json, it is read by the
+// API docs
+type Definitions struct {
+	Resources []ResourceDef               `json:"resources"`
+	Base      map[string]int32            `json:"base"`
+	Roles     map[string]map[string]int32 `json:"roles"`
+	Scopes    []*perm.ScopeInfo           `json:"scopes"`
+}
+
+// ResourceDef describes a permission field
+type ResourceDef struct {
+	Name      string `json:"name"`
+	SnakeName string `json:"snake_name"`
+	Number    int32  `json:"number"`
+}
+
+func runJSON(args []string) {
+	fs := flag.NewFlagSet("json", flag.ExitOnError)
+	lang := fs.String("lang", "en", "language of the scope titles and descriptions")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm json:\n")
+		fmt.Fprintf(os.Stderr, "\tperm json [flags]\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	defs := Definitions{
+		Resources: make([]ResourceDef, 0, len(perm.Resources)),
+		Roles:     make(map[string]map[string]int32),
+		Scopes:    perm.DescribeScopes(*lang),
+	}
+	for _, r := range perm.Resources {
+		defs.Resources = append(defs.Resources, ResourceDef{Name: r.Name, SnakeName: r.SnakeName, Number: r.Number})
+	}
+
+	base := perm.MakeBase()
+	defs.Base = permissionMap(&base)
+	for _, name := range perm.RoleNames() {
+		role, _ := perm.Role(name)
+		defs.Roles[name] = permissionMap(role)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(defs); err != nil {
+		log.Fatalf("encoding definitions: %s", err)
+	}
+}
+
+// permissionMap converts p to a map of snake case resource name to its
+// permission, resources without any permission are left out
+func permissionMap(p *common.Permission) map[string]int32 {
+	m := make(map[string]int32)
+	for _, r := range perm.Resources {
+		if num := r.Get(p); num != 0 {
+			m[r.SnakeName] = num
+		}
+	}
+	return m
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/subiz/perm"
+)
+
+func runLint(args []string) {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	src := fs.String("src", "", "directory of Go files whose ToPerm string literals are checked as well")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of perm lint:\n")
+		fmt.Fprintf(os.Stderr, "\tperm lint [flags]\n")
+		fmt.Fprintf(os.Stderr, "Exit status is 1 when any issue is found.\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	issues := perm.Lint()
+	if *src != "" {
+		srcIssues, err := lintSource(*src)
+		if err != nil {
+			log.Fatalf("parsing source: %s", err)
+		}
+		issues = append(srcIssues, issues...)
+	}
+
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
+
+// lintSource checks every string literal passed to ToPerm in the Go files of dir
+func lintSource(dir string) ([]perm.Issue, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]perm.Issue, 0)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok || len(call.Args) != 1 || !isToPerm(call.Fun) {
+					return true
+				}
+
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+
+				s, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return true
+				}
+
+				for _, problem := range perm.LintPermString(s) {
+					issues = append(issues, perm.Issue{Source: fset.Position(lit.Pos()).String(), Message: problem})
+				}
+				return true
+			})
+		}
+	}
+	return issues, nil
+}
+
+func isToPerm(fun ast.Expr) bool {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return f.Name == "ToPerm"
+	case *ast.SelectorExpr:
+		return f.Sel.Name == "ToPerm"
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+// Command perm is a toolbox for inspecting and debugging permissions
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string)
+}
+
+var commands = []command{
+	{"explain", "explain why a credential can or can't do an action", runExplain},
+	{"decode", "print a protobuf permission blob in human-readable form", runDecode},
+	{"encode", "convert a human-readable permission to a protobuf blob", runEncode},
+	{"diff", "show actions added or removed between two permissions", runDiff},
+	{"lint", "check Base, predefined roles and scopes for mistakes", runLint},
+	{"json", "print resources, Base, predefined roles and scopes in JSON", runJSON},
+}
+
+// Usage is a replacement usage function for the flags package.
+func Usage() {
+	fmt.Fprintf(os.Stderr, "Usage of perm:\n")
+	fmt.Fprintf(os.Stderr, "\tperm <command> [flags] [arguments]\n")
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-10s %s\n", c.name, c.usage)
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("perm: ")
+	if len(os.Args) < 2 {
+		Usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			c.run(os.Args[2:])
+			return
+		}
+	}
+	Usage()
+	os.Exit(2)
+}
+
+// decodeBlob decodes a hex or base64 encoded protobuf blob
+func decodeBlob(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(s); err == nil {
+		return b, nil
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%q is neither hex nor base64", s)
+}
+
+// readInput returns the content of file name, or stdin if name is "-"
+func readInput(name string) (string, error) {
+	var b []byte
+	var err error
+	if name == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(name)
+	}
+	return string(b), err
+}
+
+func splitList(s string) []string {
+	out := make([]string, 0)
+	for _, i := range strings.Split(s, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			out = append(out, i)
+		}
+	}
+	return out
+}
@@ -0,0 +1,465 @@
+package perm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Condition is a compiled boolean expression over the attributes of a
+// resource. The language is deliberately small:
+//   literals:    12, -3.5, "open", 'open', true, false
+//   attributes:  status, invoice.amount (looked up in the attribute map)
+//   comparison:  == != < <= > >=
+//   logic:       ! && || and parentheses
+// e.g.: status == "open" && (amount < 500 || vip)
+//
+// There is no arithmetic. Attributes are bool, string or any integer or
+// float type, numbers are compared as float64 so integers beyond 2^53 lose
+// precision.
+//
+// Evaluation is deterministic: operands are evaluated left to right, && and
+// || short-circuit, and a missing attribute or a type mismatch is an error,
+// which a check treats as deny
+type Condition struct {
+	Expr string
+	root node
+}
+
+// ParseCondition compiles expr
+func ParseCondition(expr string) (*Condition, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at %d", p.tokens[p.pos].text, p.tokens[p.pos].pos)
+	}
+	return &Condition{Expr: expr, root: root}, nil
+}
+
+// Eval evaluates the condition against attrs
+func (c *Condition) Eval(attrs map[string]interface{}) (bool, error) {
+	v, err := c.root.eval(attrs)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%q is not a boolean expression", c.Expr)
+	}
+	return b, nil
+}
+
+func (c *Condition) String() string { return c.Expr }
+
+type conditionRegistry struct {
+	conditions map[string]*Condition // name => condition
+	grants     map[string][]string   // resource:action => condition names
+}
+
+var conditions = newHolder(newConditionRegistry())
+
+func newConditionRegistry() *conditionRegistry {
+	return &conditionRegistry{
+		conditions: map[string]*Condition{},
+		grants:     map[string][]string{},
+	}
+}
+
+func grantKey(resource string, action int32) string {
+	return resource + ":" + strconv.Itoa(int(action))
+}
+
+// copy returns a copy of r for writers to modify
+func (r *conditionRegistry) copy() *conditionRegistry {
+	out := &conditionRegistry{
+		conditions: make(map[string]*Condition, len(r.conditions)),
+		grants:     make(map[string][]string, len(r.grants)),
+	}
+	for name, c := range r.conditions {
+		out.conditions[name] = c
+	}
+	for key, names := range r.grants {
+		out.grants[key] = names
+	}
+	return out
+}
+
+// RegisterCondition compiles expr and registers it as predicate name,
+// replacing any previous predicate of the same name
+func RegisterCondition(name, expr string) error {
+	c, err := ParseCondition(expr)
+	if err != nil {
+		return fmt.Errorf("condition %s: %s", name, err)
+	}
+
+	return conditions.update(func(cur interface{}) (interface{}, error) {
+		reg := cur.(*conditionRegistry).copy()
+		reg.conditions[name] = c
+		return reg, nil
+	})
+}
+
+// AttachCondition requires predicate name to hold, in addition to the
+// permission bits, for action on resource. resource and action are accepted
+// in the same forms as ExplainCheck. A grant may have several predicates,
+// all of them must hold
+func AttachCondition(resource, action, name string) error {
+	r, err := FindResource(resource)
+	if err != nil {
+		return err
+	}
+
+	required, err := parseAction(action)
+	if err != nil {
+		return err
+	}
+
+	return conditions.update(func(cur interface{}) (interface{}, error) {
+		reg := cur.(*conditionRegistry)
+		if reg.conditions[name] == nil {
+			return nil, fmt.Errorf("undefined condition %q", name)
+		}
+
+		key := grantKey(r.Name, required)
+		for _, n := range reg.grants[key] {
+			if n == name {
+				return reg, nil
+			}
+		}
+
+		reg = reg.copy()
+		reg.grants[key] = append(append([]string{}, reg.grants[key]...), name)
+		return reg, nil
+	})
+}
+
+// ResetConditions removes all predicates and their attachments
+func ResetConditions() {
+	conditions.store(newConditionRegistry())
+}
+
+// checkConditions evaluates, in attachment order, the predicates attached
+// to action required on resource. It returns the name of the first one
+// which does not hold
+func checkConditions(resource string, required int32, attrs map[string]interface{}) (string, error) {
+	reg := conditions.load().(*conditionRegistry)
+	for _, name := range reg.grants[grantKey(resource, required)] {
+		ok, err := reg.conditions[name].Eval(attrs)
+		if err != nil {
+			return name, err
+		}
+		if !ok {
+			return name, fmt.Errorf("%s is false", reg.conditions[name])
+		}
+	}
+	return "", nil
+}
+
+type token struct {
+	kind int // one of tok*
+	text string
+	pos  int
+}
+
+const (
+	tokNumber = iota
+	tokString
+	tokIdent
+	tokOp
+)
+
+func tokenize(expr string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			j := i + 1
+			for j < len(expr) && (expr[j] >= '0' && expr[j] <= '9' || expr[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokNumber, expr[i:j], i})
+			i = j
+		case c == '"' || c == '\'':
+			j := strings.IndexByte(expr[i+1:], c)
+			if j < 0 {
+				return nil, fmt.Errorf("unterminated string at %d", i)
+			}
+			tokens = append(tokens, token{tokString, expr[i+1 : i+1+j], i})
+			i += j + 2
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '.' || expr[j] >= 'a' && expr[j] <= 'z' ||
+				expr[j] >= 'A' && expr[j] <= 'Z' || expr[j] >= '0' && expr[j] <= '9') {
+				j++
+			}
+			tokens = append(tokens, token{tokIdent, expr[i:j], i})
+			i = j
+		default:
+			op := ""
+			for _, o := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"} {
+				if strings.HasPrefix(expr[i:], o) {
+					op = o
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+type node interface {
+	eval(attrs map[string]interface{}) (interface{}, error)
+}
+
+type literal struct{ v interface{} }
+
+func (l literal) eval(map[string]interface{}) (interface{}, error) { return l.v, nil }
+
+type attribute string
+
+func (a attribute) eval(attrs map[string]interface{}) (interface{}, error) {
+	v, ok := attrs[string(a)]
+	if !ok {
+		return nil, fmt.Errorf("missing attribute %q", string(a))
+	}
+
+	switch v := v.(type) {
+	case bool, string, float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	}
+	return nil, fmt.Errorf("attribute %q has unsupported type %T", string(a), v)
+}
+
+type not struct{ x node }
+
+func (n not) eval(attrs map[string]interface{}) (interface{}, error) {
+	v, err := n.x.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("! expects a boolean, got %v", v)
+	}
+	return !b, nil
+}
+
+type logical struct {
+	op   string // && or ||
+	x, y node
+}
+
+func (l logical) eval(attrs map[string]interface{}) (interface{}, error) {
+	x, err := l.operand(l.x, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	// short-circuit
+	if x == (l.op == "||") {
+		return x, nil
+	}
+
+	y, err := l.operand(l.y, attrs)
+	if err != nil {
+		return nil, err
+	}
+	return y, nil
+}
+
+func (l logical) operand(n node, attrs map[string]interface{}) (bool, error) {
+	v, err := n.eval(attrs)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%s expects booleans, got %v", l.op, v)
+	}
+	return b, nil
+}
+
+type comparison struct {
+	op   string
+	x, y node
+}
+
+func (c comparison) eval(attrs map[string]interface{}) (interface{}, error) {
+	x, err := c.x.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := c.y.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x := x.(type) {
+	case float64:
+		if y, ok := y.(float64); ok {
+			return compare(c.op, x < y, x == y)
+		}
+	case string:
+		if y, ok := y.(string); ok {
+			return compare(c.op, x < y, x == y)
+		}
+	case bool:
+		if y, ok := y.(bool); ok && (c.op == "==" || c.op == "!=") {
+			return compare(c.op, false, x == y)
+		}
+	}
+	return nil, fmt.Errorf("can't compare %v %s %v", x, c.op, y)
+}
+
+func compare(op string, less, equal bool) (bool, error) {
+	switch op {
+	case "==":
+		return equal, nil
+	case "!=":
+		return !equal, nil
+	case "<":
+		return less, nil
+	case "<=":
+		return less || equal, nil
+	case ">":
+		return !less && !equal, nil
+	}
+	// >=
+	return !less, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokOp && p.tokens[p.pos].text == op
+}
+
+func (p *parser) parseOr() (node, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.peek("||") {
+		p.pos++
+		var y node
+		if y, err = p.parseAnd(); err == nil {
+			x = logical{"||", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) parseAnd() (node, error) {
+	x, err := p.parseUnary()
+	for err == nil && p.peek("&&") {
+		p.pos++
+		var y node
+		if y, err = p.parseUnary(); err == nil {
+			x = logical{"&&", x, y}
+		}
+	}
+	return x, err
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.peek("!") {
+		p.pos++
+		x, err := p.parseUnary()
+		return not{x}, err
+	}
+
+	x, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.peek(op) {
+			p.pos++
+			y, err := p.parseOperand()
+			return comparison{op, x, y}, err
+		}
+	}
+	return x, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+
+	t := p.tokens[p.pos]
+	p.pos++
+	switch t.kind {
+	case tokNumber:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", t.text, t.pos)
+		}
+		return literal{f}, nil
+	case tokString:
+		return literal{t.text}, nil
+	case tokIdent:
+		if t.text == "true" || t.text == "false" {
+			return literal{t.text == "true"}, nil
+		}
+		return attribute(t.text), nil
+	}
+
+	if t.text == "(" {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.peek(")") {
+			return nil, fmt.Errorf("missing ) for ( at %d", t.pos)
+		}
+		p.pos++
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
@@ -0,0 +1,180 @@
+// Package core evaluates permissions without depending on any protobuf
+// type. It only imports the standard library, callers describe themselves
+// through the small Principal interface, and optionally Relater and Guard.
+// Package perm implements them for github.com/subiz/header/common
+// credentials, with its ownership resolvers, service accounts and
+// conditions, and runs its Check functions through Check.
+//
+// core is a module of its own, github.com/subiz/perm/core, which requires
+// nothing, so importing it doesn't add github.com/subiz/header nor any other
+// requirement of perm to the importer's go.mod
+package core
+
+import "errors"
+
+// Principal is the caller of a check
+type Principal interface {
+	AccountId() string
+	Issuer() string
+	// Perm returns the permission of the principal on resource, e.g.
+	// conversation, in either V1 or V2 encoding, 0 if it has none
+	Perm(resource string) int64
+}
+
+// the permission bits of the actions, the n-th extra action uses the n-th
+// bit above delete
+const (
+	Create int32 = 0x8
+	Read   int32 = 0x4
+	Update int32 = 0x2
+	Delete int32 = 0x1
+)
+
+// ErrDenied is returned when the principal is not allowed to do the action
+var ErrDenied = errors.New("not enough permission")
+
+// Target describes the instance of a resource a check is evaluated against
+type Target struct {
+	AccountId string   // account owning the resource
+	Owners    []string // ids owning the resource, the caller's issuer by default
+
+	// Groups are the agent groups owning the resource, CallerGroups the
+	// groups the caller is a member of. Sharing one of them grants the g:
+	// (agent group) permission
+	Groups       []string
+	CallerGroups []string
+
+	// Attributes of the resource, e.g. {"status": "open"}, for principals
+	// implementing Guard
+	Attributes map[string]interface{}
+}
+
+// InGroup reports whether one of the caller's groups owns the resource
+func (t Target) InGroup() bool {
+	for _, group := range t.CallerGroups {
+		if contains(group, t.Groups) {
+			return true
+		}
+	}
+	return false
+}
+
+// Relater is implemented by principals which decide how they relate to t,
+// an instance of resource, e.g. ones owning resources through a delegation
+// or ones which never own anything. A non nil error denies the check. Other
+// principals own the resource when their issuer is one of the owners
+type Relater interface {
+	Relate(resource string, t Target) (ismine, ingroup, sameaccount bool, err error)
+}
+
+// Guard is implemented by principals with requirements beyond the
+// permission bits, e.g. conditions on the attributes of the target. It is
+// only called once the bits allow the action, a non nil error denies it
+type Guard interface {
+	Guard(resource string, required int32, t Target) error
+}
+
+// Check checks whether p may do action required on t, an instance of
+// resource
+func Check(p Principal, resource string, required int32, t Target) error {
+	if p == nil {
+		return ErrDenied
+	}
+
+	ismine, ingroup, sameaccount, err := Relate(p, resource, t)
+	if err != nil {
+		return err
+	}
+
+	if err := CheckPerm(required, p.Perm(resource), ismine, ingroup, sameaccount); err != nil {
+		return err
+	}
+
+	if g, ok := p.(Guard); ok {
+		return g.Guard(resource, required, t)
+	}
+	return nil
+}
+
+// Relate computes how p relates to t, an instance of resource: whether it
+// owns t, whether one of its groups owns t and whether both are in the same
+// account
+func Relate(p Principal, resource string, t Target) (ismine, ingroup, sameaccount bool, err error) {
+	if r, ok := p.(Relater); ok {
+		return r.Relate(resource, t)
+	}
+
+	sameaccount = p.AccountId() == t.AccountId
+	ismine = sameaccount && contains(p.Issuer(), t.Owners)
+	return ismine, sameaccount && t.InGroup(), sameaccount, nil
+}
+
+// CheckPerm evaluates the levels of callerperm, in either V1 or V2 encoding:
+// s: allows everything, the others only within the caller's account, u:
+// when the caller owns the resource, g: when one of its groups does, a:
+// always
+func CheckPerm(required int32, callerperm int64, ismine, ingroup, sameaccount bool) error {
+	return TraceCheckPerm(required, callerperm, ismine, ingroup, sameaccount, nil)
+}
+
+// Step is a stage of CheckPerm, either the comparison of the required
+// actions with the actions of a level or a fact about the caller
+type Step struct {
+	Name  string // super level, account match, ownership, user level, group, group level or account level
+	Level string // level compared, empty for the facts
+	Has   int32  // actions of Level
+	Pass  bool
+}
+
+// TraceCheckPerm is CheckPerm calling trace, when not nil, with every step it
+// takes
+func TraceCheckPerm(required int32, callerperm int64, ismine, ingroup, sameaccount bool, trace func(Step)) error {
+	level := func(name, level string) bool {
+		has := GetPerm(level, callerperm)
+		pass := required&has == required
+		if trace != nil {
+			trace(Step{Name: name, Level: level, Has: has, Pass: pass})
+		}
+		return pass
+	}
+
+	fact := func(name string, pass bool) bool {
+		if trace != nil {
+			trace(Step{Name: name, Pass: pass})
+		}
+		return pass
+	}
+
+	// check super perm first
+	if level("super level", "s") {
+		return nil
+	}
+
+	if !fact("account match", sameaccount) {
+		return ErrDenied
+	}
+
+	// check my resource permission
+	if fact("ownership", ismine) && level("user level", "u") {
+		return nil
+	}
+
+	// check my group's resource permission
+	if fact("group", ingroup) && level("group level", "g") {
+		return nil
+	}
+
+	if level("account level", "a") {
+		return nil
+	}
+	return ErrDenied
+}
+
+func contains(s string, ss []string) bool {
+	for _, i := range ss {
+		if i == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+type principal struct {
+	accid, issuer string
+	perm          map[string]int64
+}
+
+func (p principal) AccountId() string          { return p.accid }
+func (p principal) Issuer() string             { return p.issuer }
+func (p principal) Perm(resource string) int64 { return p.perm[resource] }
+
+func TestCheck(t *testing.T) {
+	v1 := int64(MakePerm("u", Read|Update) | MakePerm("a", Read) | MakePerm("g", Delete))
+	p := principal{"ac1", "ag1", map[string]int64{
+		"conversation": v1,
+		"user":         ToV2(int32(v1)),
+		"account":      int64(MakePerm("s", Read)),
+	}}
+
+	tcs := []struct {
+		resource string
+		required int32
+		target   Target
+		allowed  bool
+	}{
+		{"conversation", Read, Target{AccountId: "ac1"}, true},
+		{"conversation", Update, Target{AccountId: "ac1"}, false},
+		{"conversation", Update, Target{AccountId: "ac1", Owners: []string{"ag1"}}, true},
+		{"conversation", Update, Target{AccountId: "ac2", Owners: []string{"ag1"}}, false},
+		{"conversation", Delete, Target{AccountId: "ac1", Groups: []string{"gr1"}, CallerGroups: []string{"gr1"}}, true},
+		{"conversation", Delete, Target{AccountId: "ac1", Groups: []string{"gr1"}, CallerGroups: []string{"gr2"}}, false},
+		{"user", Update, Target{AccountId: "ac1", Owners: []string{"ag1"}}, true},
+		{"user", Create, Target{AccountId: "ac1", Owners: []string{"ag1"}}, false},
+		{"account", Read, Target{AccountId: "ac2"}, true},
+		{"unknown", Read, Target{AccountId: "ac1"}, false},
+	}
+	for i, tc := range tcs {
+		err := Check(p, tc.resource, tc.required, tc.target)
+		if (err == nil) != tc.allowed {
+			t.Errorf("case %d: got %v, expect allowed %v", i, err, tc.allowed)
+		}
+		if err != nil && err != ErrDenied {
+			t.Errorf("case %d: unexpected error %v", i, err)
+		}
+	}
+
+	if Check(nil, "conversation", Read, Target{}) != ErrDenied {
+		t.Error("expect nil principal to be denied")
+	}
+
+	// everyone in the account owns everything
+	if err := Check(owner{p}, "conversation", Update, Target{AccountId: "ac1"}); err != nil {
+		t.Error(err)
+	}
+
+	if err := Check(owner{p}, "conversation", Update, Target{AccountId: "ac2"}); err != ErrDenied {
+		t.Errorf("expect denied, got %v", err)
+	}
+
+	// guarded principal
+	open := map[string]interface{}{"status": "open"}
+	if err := Check(guarded{p}, "conversation", Read, Target{AccountId: "ac1", Attributes: open}); err != nil {
+		t.Error(err)
+	}
+
+	if err := Check(guarded{p}, "conversation", Read, Target{AccountId: "ac1"}); err == nil || err == ErrDenied {
+		t.Errorf("expect guard error, got %v", err)
+	}
+}
+
+// owner owns everything in its account
+type owner struct{ principal }
+
+func (o owner) Relate(resource string, t Target) (bool, bool, bool, error) {
+	return o.accid == t.AccountId, false, o.accid == t.AccountId, nil
+}
+
+// guarded only acts on open resources
+type guarded struct{ principal }
+
+func (g guarded) Guard(resource string, required int32, t Target) error {
+	if t.Attributes["status"] != "open" {
+		return errors.New("resource is not open")
+	}
+	return nil
+}
+
+func TestEncoding(t *testing.T) {
+	for _, num := range []int32{0, 0x1, 0x7FF, MakePerm("g", 0xFF) | MakePerm("s", 0x5A)} {
+		v2 := ToV2(num)
+		if Version(v2) != V2 || Version(int64(num)) != V1 {
+			t.Errorf("%#x: wrong version", num)
+		}
+		for _, level := range []string{"u", "a", "s", "g"} {
+			if GetPerm(level, v2) != GetPerm(level, int64(num)) {
+				t.Errorf("%#x: level %s differs", num, level)
+			}
+		}
+		back, err := ToV1(v2)
+		if err != nil || back != num {
+			t.Errorf("%#x: got %#x %v", num, back, err)
+		}
+	}
+
+	// bit 31 set, widened either way
+	neg := MakePerm("g", 0xF0) | MakePerm("s", 0x4)
+	for _, num := range []int64{int64(uint32(neg)), int64(neg)} {
+		if Version(num) != V1 || GetPerm("g", num) != 0xF0 || GetPerm("s", num) != 0x4 {
+			t.Errorf("%#x: wrong levels", num)
+		}
+		if back, err := ToV1(num); err != nil || back != neg {
+			t.Errorf("%#x: got %#x %v", num, back, err)
+		}
+	}
+
+	if back, err := ToV1(ToV2(neg)); err != nil || back != neg || GetPerm("g", ToV2(neg)) != 0xF0 {
+		t.Errorf("%#x: got %#x %v", neg, back, err)
+	}
+
+	if _, err := ToV1(1 << 33); err == nil {
+		t.Error("expect error for values wider than 32 bits")
+	}
+
+	if _, err := ToV1(ToV2(0) | 1<<40); err == nil {
+		t.Error("expect error for levels V1 can't hold")
+	}
+}
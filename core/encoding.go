@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	"math"
+)
+
+// Permissions are encoded in one of two layouts, both give every level 8 bits
+// of actions: the lower 4 are create, read, update, delete and the upper 4
+// are the extra actions.
+//
+// V1 fits in an int32. Each level has a crud nibble (u: bits 0-3, a: bits
+// 4-7, s: bits 8-11) and an extra actions nibble 12 bits above it. The g:
+// level took bits 24-27 and 28-31, the last free ones, so every bit is in
+// use: a new level or a fifth extra action needs V2.
+//
+// V2 is an int64 with 8 contiguous bits per level (u: bits 0-7, a: bits 8-15,
+// s: bits 16-23, g: bits 24-31), bits 32-55 are reserved for new levels. The
+// top byte holds the version so both encodings can be told apart while
+// migrating. A V1 value must be widened with int64(uint32(v)), which leaves
+// the top byte zero, a plain int64(v) sign extends the value once bit 31 (the
+// last extra action of g:) is set.
+const (
+	V1 = 1
+	V2 = 2
+)
+
+const versionShift = 56
+
+// v2Levels lists the levels of the V2 encoding, the n-th level uses the
+// n-th byte
+var v2Levels = []string{"u", "a", "s", "g"}
+
+// Version returns the encoding of num
+func Version(num int64) int {
+	if num>>versionShift == V2 {
+		return V2
+	}
+	return V1
+}
+
+// levelShift returns the offsets of the create, read, update, delete nibble
+// and of the extra actions nibble of level r in V1 encoding. ok is false for
+// unknown levels
+func levelShift(r string) (crud, extra uint, ok bool) {
+	if r == "u" {
+		return 0, 12, true
+	} else if r == "a" {
+		return 4, 16, true
+	} else if r == "s" {
+		return 8, 20, true
+	} else if r == "g" {
+		return 24, 28, true
+	}
+	return 0, 0, false
+}
+
+// GetPerm returns the permission of level r in num, the lower 4 bits are
+// create, read, update, delete and the upper 4 bits are the extra actions.
+// num is either in V1 or V2 encoding
+func GetPerm(r string, num int64) int32 {
+	if Version(num) == V2 {
+		for i, level := range v2Levels {
+			if level == r {
+				return int32(num >> uint(8*i) & 0xFF)
+			}
+		}
+		return 0
+	}
+
+	crud, extra, ok := levelShift(r)
+	if !ok {
+		return 0
+	}
+	v1 := int32(num)
+	return (v1>>crud)&0xF | (v1>>extra)&0xF<<4
+}
+
+// MakePerm is the reverse of GetPerm, it places permission p at level r in
+// V1 encoding
+func MakePerm(r string, p int32) int32 {
+	crud, extra, ok := levelShift(r)
+	if !ok {
+		return 0
+	}
+	return (p&0xF)<<crud | (p>>4&0xF)<<extra
+}
+
+// ToV2 converts permission num from V1 to V2 encoding
+func ToV2(num int32) int64 {
+	out := int64(V2) << versionShift
+	for i, level := range v2Levels {
+		out |= int64(GetPerm(level, int64(uint32(num)))) << uint(8*i)
+	}
+	return out
+}
+
+// ToV1 converts permission num to V1 encoding. It fails when num uses bits
+// that V1 has no room for
+func ToV1(num int64) (int32, error) {
+	if Version(num) == V1 {
+		// either widened from uint32 or sign extended
+		if num < math.MinInt32 || num > math.MaxUint32 {
+			return 0, fmt.Errorf("%#x is not a valid permission", num)
+		}
+		return int32(num), nil
+	}
+
+	payload := num & (1<<versionShift - 1)
+	if payload>>uint(8*len(v2Levels)) != 0 {
+		return 0, fmt.Errorf("%#x uses levels which V1 encoding can't hold", num)
+	}
+
+	out := int32(0)
+	for _, level := range v2Levels {
+		out |= MakePerm(level, GetPerm(level, num))
+	}
+	return out, nil
+}
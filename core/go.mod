@@ -0,0 +1,3 @@
+module github.com/subiz/perm/core
+
+go 1.13
@@ -0,0 +1,59 @@
+package core
+
+import "strings"
+
+// ParseActions converts action letters, e.g. -ru-, to permission bits. extra
+// lists the letters of the actions beyond create, read, update and delete,
+// the n-th letter uses the n-th bit above delete
+func ParseActions(p, extra string) int32 {
+	out := int32(0)
+	if strings.Contains(p, "c") {
+		out |= Create
+	}
+
+	if strings.Contains(p, "r") {
+		out |= Read
+	}
+
+	if strings.Contains(p, "u") {
+		out |= Update
+	}
+
+	if strings.Contains(p, "d") {
+		out |= Delete
+	}
+
+	for i, l := range extra {
+		if strings.ContainsRune(p, l) {
+			out |= 0x10 << uint(i)
+		}
+	}
+	return out
+}
+
+// ParsePerm converts permission in string representation, e.g. u:-ru- a:-r--,
+// to V1 encoding. Unknown levels are ignored
+func ParsePerm(p, extra string) int32 {
+	rawperms := strings.Split(strings.TrimSpace(p), " ")
+	um, gm, am, sm := "", "", "", ""
+	for _, perm := range rawperms {
+		perm = strings.TrimSpace(strings.ToLower(perm))
+		if len(perm) < 2 {
+			continue
+		}
+
+		if perm[0] == 'u' {
+			um += perm[1:]
+		} else if perm[0] == 'g' {
+			gm += perm[1:]
+		} else if perm[0] == 'a' {
+			am += perm[1:]
+		} else if perm[0] == 's' {
+			sm += perm[1:]
+		} else {
+			continue
+		}
+	}
+	return MakePerm("u", ParseActions(um, extra)) | MakePerm("g", ParseActions(gm, extra)) |
+		MakePerm("a", ParseActions(am, extra)) | MakePerm("s", ParseActions(sm, extra))
+}
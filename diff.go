@@ -0,0 +1,81 @@
+package perm
+
+import (
+	"sort"
+
+	"github.com/subiz/header/common"
+)
+
+// Change describes the actions added or removed on a resource at a single
+// level (u, g, a or s) between two permissions
+type Change struct {
+	Resource string
+	Level    string
+	Added    int32
+	Removed  int32
+}
+
+// String prints the change in form of "agent a: +cu -d"
+func (c Change) String() string {
+	out := c.Resource + " " + c.Level + ":"
+	if c.Added != 0 {
+		out += " +" + actionLetters(c.Added)
+	}
+	if c.Removed != 0 {
+		out += " -" + actionLetters(c.Removed)
+	}
+	return out
+}
+
+// Diff returns the changes needed to turn permission a into permission b,
+// ordered by the snake case name of the resource then by level (u, g, a, s)
+func Diff(a, b *common.Permission) []Change {
+	changes := make([]Change, 0)
+	for _, r := range Resources {
+		numa, numb := r.Get(a), r.Get(b)
+		for _, level := range []string{"u", "g", "a", "s"} {
+			pa, pb := getPerm(level, int64(uint32(numa))), getPerm(level, int64(uint32(numb)))
+			if pa == pb {
+				continue
+			}
+
+			changes = append(changes, Change{
+				Resource: r.SnakeName,
+				Level:    level,
+				Added:    pb &^ pa,
+				Removed:  pa &^ pb,
+			})
+		}
+	}
+
+	// the levels of a resource are already in order
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Resource < changes[j].Resource })
+	return changes
+}
+
+// Escalates reports whether any of the changes grants a new action
+func Escalates(changes []Change) bool {
+	for _, c := range changes {
+		if c.Added != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// actionLetters converts permission bits to its action letters, e.g.: 0xA => cu
+func actionLetters(num int32) string {
+	out := ""
+	for i, l := range "crud" {
+		if num&(8>>uint(i)) != 0 {
+			out += string(l)
+		}
+	}
+
+	for i, l := range extraActions {
+		if num&(0x10<<uint(i)) != 0 {
+			out += string(l)
+		}
+	}
+	return out
+}
@@ -0,0 +1,163 @@
+package perm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/subiz/header/common"
+)
+
+// scopeActions converts the actions of a scope entry, r (read), w (write:
+// create, update, delete) or an extra action letter, to permission bits
+func scopeActions(actions string) int32 {
+	out := int32(0)
+	for _, a := range actions {
+		if a == 'w' {
+			out |= CREATEPERM | UPDATEPERM | DELETEPERM
+		} else if a == 'r' || strings.ContainsRune(extraActions, a) {
+			out |= strPermToInt(string(a))
+		}
+	}
+	return out
+}
+
+// scopeGrant is one action of a scope entry, compiled to permission bits of
+// a resource
+type scopeGrant struct {
+	scope    string
+	item     string // the scope entry and action, e.g. conversation:w
+	resource *Resource
+	actions  int32
+	levels   []string // u and g, or a
+	perm     int32    // actions at every level
+}
+
+// heldBy reports whether callerperm allows, at one of the levels of g, every
+// action of g that base allows at that level. Actions base never allows can't
+// be held by anyone, so a grant made only of them is never held
+func (g scopeGrant) heldBy(callerperm, base int64) bool {
+	for _, level := range g.levels {
+		required := g.actions & getPerm(level, base)
+		if required != 0 && getPerm(level, callerperm)&required == required {
+			return true
+		}
+	}
+	return false
+}
+
+// compileScope splits scope into its grants. An entry such as
+// conversation:rw allows the actions on the caller's own resources and its
+// groups' resources (u: and g:), other_conversation:rw allows them on every
+// resource of the account (a:). Resources no one can own, those Base grants
+// nothing at u:, are account wide, so conversation:rw means a: for them.
+// Patterns are expanded to every resource they cover. Sub-resources, such
+// as conversation.message, have no permission field, they are only enforced
+// by Access. Scopes never allow super (s:) actions
+func (p *Policy) compileScope(scope string) ([]scopeGrant, error) {
+	set, ok := p.scopes[strings.TrimSpace(scope)]
+	if !ok {
+		return nil, fmt.Errorf("unknown scope %q", scope)
+	}
+
+	grants := make([]scopeGrant, 0)
+	for _, entry := range set.Resources() {
+		matched := false
+		for i := range Resources {
+			r := &Resources[i]
+			base := getPerm("u", int64(uint32(r.Get(p.base))))
+			own := covers(entry, r.SnakeName)
+			other := covers(entry, "other_"+r.SnakeName)
+			if !own && !other {
+				if covers(r.SnakeName, entry) || covers("other_"+r.SnakeName, entry) {
+					matched = true // a sub-resource
+				}
+				continue
+			}
+			matched = true
+
+			for _, a := range set.Actions(entry) {
+				actions := scopeActions(string(a))
+				if actions == 0 {
+					continue
+				}
+
+				levels := []string{"a"}
+				if own && base != 0 {
+					levels = []string{"u", "g"}
+				}
+
+				num := int32(0)
+				for _, level := range levels {
+					num |= makePerm(level, actions)
+				}
+				grants = append(grants, scopeGrant{scope, entry + ":" + string(a), r, actions, levels, num})
+
+				// a pattern, e.g. *, may cover both conversation and
+				// other_conversation
+				if other && own && base != 0 {
+					grants = append(grants, scopeGrant{scope, entry + ":" + string(a), r, actions, []string{"a"}, makePerm("a", actions)})
+				}
+			}
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("scope %s: %q does not match any permission field", scope, entry)
+		}
+	}
+	return grants, nil
+}
+
+// CompileScopes converts scopes to the permission they allow, see
+// compileScope
+func CompileScopes(scopes []string) (*common.Permission, error) {
+	policy := CurrentPolicy()
+	p := &common.Permission{}
+	for _, scope := range scopes {
+		grants, err := policy.compileScope(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, g := range grants {
+			g.resource.Set(p, g.resource.Get(p)|g.perm)
+		}
+	}
+	return p, nil
+}
+
+// Downscope mints a credential for a delegated token, e.g. one issued to an
+// integration on behalf of cred. The token only allows what scopes allow,
+// intersected with the permission of cred, so it can never exceed its
+// issuer. It returns nil if a scope is unknown or the caller does not hold
+// it: for an entry such as conversation:w, the caller must be able to create,
+// update and delete its own conversations (u:) or its groups' (g:), except
+// for the actions Base never allows at that level. Levels are not combined,
+// and a: or s: don't stand in for u: and g:, as the token only keeps the
+// levels the scope grants
+func Downscope(cred *common.Credential, scopes []string) *common.Credential {
+	if cred == nil {
+		return nil
+	}
+
+	policy := CurrentPolicy()
+	requested := &common.Permission{}
+	for _, scope := range scopes {
+		grants, err := policy.compileScope(scope)
+		if err != nil {
+			return nil
+		}
+
+		for _, g := range grants {
+			callerperm := int64(uint32(g.resource.Get(cred.GetPerm())))
+			if !g.heldBy(callerperm, int64(uint32(g.resource.Get(policy.base)))) {
+				return nil
+			}
+			g.resource.Set(requested, g.resource.Get(requested)|g.perm)
+		}
+	}
+
+	out := proto.Clone(cred).(*common.Credential)
+	out.Perm = IntersectPermission(requested, cred.GetPerm())
+	return out
+}
@@ -0,0 +1,56 @@
+package perm
+
+import (
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
+)
+
+// Permissions are encoded in one of two layouts, V1 fits in the int32 fields
+// of common.Permission, V2 is an int64 with room for more levels. See
+// package core for the layouts.
+//
+// Migrating to V2: the fields of common.Permission are still int32, so the
+// generated Check functions only read V1 values. Until they become int64,
+// permissions stored as V2, e.g. built with ToPerm64, are checked with
+// CheckPerm64. Once they are int64, cli/gen.go reads them without widening
+// and every Check function accepts both encodings
+const (
+	V1 = core.V1
+	V2 = core.V2
+)
+
+// Version returns the encoding of num
+func Version(num int64) int { return core.Version(num) }
+
+// ToV2 converts permission num from V1 to V2 encoding
+func ToV2(num int32) int64 { return core.ToV2(num) }
+
+// ToV1 converts permission num to V1 encoding. It fails when num uses bits
+// that V1 has no room for
+func ToV1(num int64) (int32, error) { return core.ToV1(num) }
+
+// ToPerm64 is ToPerm with V2 encoding
+func ToPerm64(p string) int64 {
+	return ToV2(ToPerm(p))
+}
+
+// FormatPerm64 is FormatPerm for permission in either V1 or V2 encoding
+func FormatPerm64(num int64) string {
+	out := "u:" + intPermToStr(getPerm("u", num))
+	if g := getPerm("g", num); g != 0 {
+		out += " g:" + intPermToStr(g)
+	}
+	return out + " a:" + intPermToStr(getPerm("a", num)) +
+		" s:" + intPermToStr(getPerm("s", num))
+}
+
+// CheckPerm64 checks whether the caller cred may do action required on t, an
+// instance of resource, like the generated Check functions but with the
+// caller's permission on resource given as perm, in either V1 or V2 encoding
+func CheckPerm64(resource string, required int32, perm int64, cred *common.Credential, t Target) error {
+	r, err := FindResource(resource)
+	if err != nil {
+		return err
+	}
+	return checkTarget(r.Name, required, perm, cred, t)
+}
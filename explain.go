@@ -0,0 +1,141 @@
+package perm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
+)
+
+// Step is a single stage of a permission evaluation
+type Step struct {
+	Name   string
+	Detail string
+	Pass   bool
+}
+
+// Explanation records how checkPerm reached its verdict
+type Explanation struct {
+	Resource    string
+	Required    int32
+	CallerPerm  int64
+	IsMine      bool
+	InGroup     bool
+	SameAccount bool
+	Steps       []Step
+	Allowed     bool
+}
+
+// String prints the evaluation steps one per line, followed by the verdict
+func (e *Explanation) String() string {
+	out := fmt.Sprintf("resource: %s, required: %s\n", e.Resource, intPermToStr(e.Required))
+	out += fmt.Sprintf("caller: %s\n", FormatPerm64(e.CallerPerm))
+	for i, step := range e.Steps {
+		verdict := "no"
+		if step.Pass {
+			verdict = "yes"
+		}
+		out += fmt.Sprintf("%d. %-15s %-4s %s\n", i+1, step.Name, verdict, step.Detail)
+	}
+	if e.Allowed {
+		return out + "verdict: allow"
+	}
+	return out + "verdict: deny"
+}
+
+// factDetails describes the steps of core.TraceCheckPerm which are not a
+// level
+var factDetails = map[string]string{
+	"account match": "caller is in the same account",
+	"ownership":     "caller owns the resource",
+	"group":         "caller's agent group owns the resource",
+}
+
+// Explain evaluates checkPerm, recording every step it takes
+func Explain(required int32, callerperm int64, ismine, ingroup, sameaccount bool) *Explanation {
+	e := &Explanation{
+		Required:    required,
+		CallerPerm:  callerperm,
+		IsMine:      ismine,
+		InGroup:     ingroup,
+		SameAccount: sameaccount,
+	}
+
+	err := core.TraceCheckPerm(required, callerperm, ismine, ingroup, sameaccount, func(s core.Step) {
+		detail := factDetails[s.Name]
+		if s.Level != "" {
+			detail = fmt.Sprintf("%s:%s covers %s", s.Level, intPermToStr(s.Has), intPermToStr(required))
+		}
+		e.Steps = append(e.Steps, Step{Name: s.Name, Detail: detail, Pass: s.Pass})
+	})
+	e.Allowed = err == nil
+	return e
+}
+
+// ExplainCheck explains the result of Check<Action><Resource>(cred, accid, agids...)
+// resource is the permission field name, either in snake case (agent_group)
+// or in camel case (AgentGroup). action is one of c, r, u, d, the letter of an
+// extra action, or their full name
+func ExplainCheck(resource, action string, cred *common.Credential, accid string, agids ...string) (*Explanation, error) {
+	return ExplainTarget(resource, action, cred, Target{AccountId: accid, Owners: agids})
+}
+
+// ExplainTarget explains the result of Check<Action><Resource>Target(cred, t)
+func ExplainTarget(resource, action string, cred *common.Credential, t Target) (*Explanation, error) {
+	callerperm, err := lookupResource(cred.GetPerm(), resource)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := parseAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	r, _ := FindResource(resource)
+	perm := boundPerm(cred, resource, int64(uint32(callerperm)))
+	ismine, ingroup, isaccount, err := relation(cred, r.Name, t)
+	if err != nil {
+		return &Explanation{
+			Resource:   resource,
+			Required:   required,
+			CallerPerm: perm,
+			Steps:      []Step{{Name: "principal", Detail: err.Error()}},
+		}, nil
+	}
+
+	e := Explain(required, perm, ismine, ingroup, isaccount)
+	e.Resource = resource
+	if e.Allowed {
+		name, err := checkConditions(r.Name, required, t.Attributes)
+		if err != nil {
+			e.Steps = append(e.Steps, Step{Name: "condition", Detail: name + ": " + err.Error()})
+			e.Allowed = false
+		}
+	}
+	return e, nil
+}
+
+// parseAction converts an action name (c, create, r, read, export...) or
+// letter, in any case, to its permission bit
+func parseAction(action string) (int32, error) {
+	action = strings.ToLower(strings.TrimSpace(action))
+	switch action {
+	case "c", "create":
+		return CREATEPERM, nil
+	case "r", "read":
+		return READPERM, nil
+	case "u", "update":
+		return UPDATEPERM, nil
+	case "d", "delete":
+		return DELETEPERM, nil
+	}
+
+	for i, name := range extraActionNames {
+		if action == name || action == extraActions[i:i+1] {
+			return 0x10 << uint(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown action %q", action)
+}
@@ -0,0 +1,57 @@
+package perm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/subiz/header/common"
+)
+
+// FormatPermission converts p to a human-readable form, one resource per
+// line, resources without any permission are left out
+// example:
+//   agent: u:-ru- a:-r-- s:----
+//   conversation: u:cru- a:-r-- s:----
+func FormatPermission(p *common.Permission) string {
+	out := ""
+	for _, r := range Resources {
+		if num := r.Get(p); num != 0 {
+			out += r.SnakeName + ": " + FormatPerm(num) + "\n"
+		}
+	}
+	return out
+}
+
+// ParsePermission is the reverse of FormatPermission. Each non-empty line
+// contains a resource name followed by a colon and its permission in the
+// format accepted by ToPerm. Lines starting with # are ignored
+func ParsePermission(s string) (*common.Permission, error) {
+	p := &common.Permission{}
+	for i, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: missing resource name in %q", i+1, line)
+		}
+
+		r, err := FindResource(strings.TrimSpace(line[:colon]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", i+1, err)
+		}
+		r.Set(p, r.Get(p)|ToPerm(line[colon+1:]))
+	}
+	return p, nil
+}
+
+// lookupResource returns the permission of the field named resource in p
+func lookupResource(p *common.Permission, resource string) (int32, error) {
+	r, err := FindResource(resource)
+	if err != nil {
+		return 0, err
+	}
+	return r.Get(p), nil
+}
@@ -1,2 +1,5 @@
 #!/bin/sh
-go run cli/gen.go -file vendor/github.com/subiz/header/common/common.pb.go -type Permission
+go run cli/gen.go -pkg github.com/subiz/header/common -type Permission -actions actions.txt
+# the TypeScript module takes Base and the roles from the package built with
+# the checkers written above
+go run ./cli/perm json | go run cli/gen.go -pkg github.com/subiz/header/common -type Permission -actions actions.txt -ts permission.ts
@@ -6,4 +6,11 @@ require (
 	github.com/golang/protobuf v1.4.3
 	github.com/subiz/errors v1.0.9
 	github.com/subiz/header v1.2.63
+	github.com/subiz/perm/core v0.0.0
+	golang.org/x/tools v0.1.0
+	google.golang.org/protobuf v1.25.0
 )
+
+// core is released with its own core/vX.Y.Z tags, importers of perm need the
+// required version to be tagged, the replace only applies within this module
+replace github.com/subiz/perm/core => ./core
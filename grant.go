@@ -0,0 +1,63 @@
+package perm
+
+import (
+	"time"
+
+	"github.com/subiz/header/common"
+)
+
+// Grant is a permission which is only effective within a time window, e.g.
+// a support agent allowed to update subscriptions for 2 hours
+type Grant struct {
+	Perm      *common.Permission
+	NotBefore time.Time // zero means the grant is effective immediately
+	NotAfter  time.Time // zero means the grant never expires
+}
+
+// Active reports whether the grant is effective at instant t. Both ends of
+// the window are inclusive
+func (g Grant) Active(t time.Time) bool {
+	if !g.NotBefore.IsZero() && t.Before(g.NotBefore) {
+		return false
+	}
+	return g.NotAfter.IsZero() || !t.After(g.NotAfter)
+}
+
+// GrantFor returns a grant of p effective from now for duration d
+func GrantFor(p *common.Permission, d time.Duration) Grant {
+	now := Now()
+	return Grant{Perm: p, NotBefore: now, NotAfter: now.Add(d)}
+}
+
+// Resolve returns the permission the grants give at instant t, the union of
+// the active grants
+func Resolve(grants []Grant, t time.Time) *common.Permission {
+	out := &common.Permission{}
+	for _, g := range grants {
+		if g.Active(t) {
+			out = Merge(out, g.Perm)
+		}
+	}
+	return out
+}
+
+// MergeGrants layers the grants active right now over base, e.g. a role
+func MergeGrants(base *common.Permission, grants ...Grant) *common.Permission {
+	return Merge(base, Resolve(grants, Now()))
+}
+
+var clock = newHolder(time.Now)
+
+// SetClock replaces the clock used to resolve grants, so tests can verify
+// expiry. A nil now restores time.Now
+func SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	clock.store(now)
+}
+
+// Now returns the current time of the clock set by SetClock
+func Now() time.Time {
+	return clock.load().(func() time.Time)()
+}
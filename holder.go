@@ -0,0 +1,46 @@
+package perm
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// holder keeps a value which every check reads and which may be replaced
+// while checks are running, e.g. the policy or the ownership resolver.
+// Loads don't lock, writers are serialized so that an update never loses a
+// concurrent write
+type holder struct {
+	mu sync.Mutex
+	v  atomic.Value // box
+}
+
+// box lets holder keep values of any type, funcs and interfaces included, as
+// atomic.Value requires the same concrete type on every store
+type box struct{ v interface{} }
+
+func newHolder(v interface{}) *holder {
+	h := &holder{}
+	h.v.Store(box{v})
+	return h
+}
+
+func (h *holder) load() interface{} { return h.v.Load().(box).v }
+
+func (h *holder) store(v interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.v.Store(box{v})
+}
+
+// update replaces the value with f of the current one. f must not modify
+// the current value, and an error from f leaves it in place
+func (h *holder) update(f func(cur interface{}) (interface{}, error)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, err := f(h.load())
+	if err != nil {
+		return err
+	}
+	h.v.Store(box{v})
+	return nil
+}
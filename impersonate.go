@@ -0,0 +1,84 @@
+package perm
+
+import (
+	"fmt"
+
+	"github.com/subiz/errors"
+	"github.com/subiz/header/common"
+)
+
+// Impersonation is a super-level actor, e.g. a support agent, acting as
+// Target, an agent of a customer account
+type Impersonation struct {
+	Actor  *common.Credential
+	Target *common.Credential
+}
+
+// Decision is the audit record of a check made under impersonation
+type Decision struct {
+	ActorAccountId  string
+	ActorIssuer     string
+	TargetAccountId string
+	TargetIssuer    string
+	Resource        string
+	Action          string
+	Allowed         bool
+	Err             error // why the action is denied
+}
+
+func (d *Decision) String() string {
+	verdict := "allow"
+	if !d.Allowed {
+		verdict = "deny: " + d.Err.Error()
+	}
+	return fmt.Sprintf("%s/%s as %s/%s %s %s: %s", d.ActorAccountId, d.ActorIssuer,
+		d.TargetAccountId, d.TargetIssuer, d.Action, d.Resource, verdict)
+}
+
+// Check checks action on t, an instance of resource, under impersonation.
+// resource and action are accepted in the same forms as ExplainCheck. It
+// passes only if the target is allowed to perform the action and the actor
+// holds the action at super (s:) level, the only level which crosses
+// accounts. The principal rules, e.g. expiry and pinning of service
+// accounts, apply to both identities
+func (i Impersonation) Check(resource, action string, t Target) *Decision {
+	d := &Decision{
+		ActorAccountId:  i.Actor.GetAccountId(),
+		ActorIssuer:     i.Actor.GetIssuer(),
+		TargetAccountId: i.Target.GetAccountId(),
+		TargetIssuer:    i.Target.GetIssuer(),
+		Resource:        resource,
+		Action:          action,
+	}
+
+	r, err := FindResource(resource)
+	if err != nil {
+		d.Err = err
+		return d
+	}
+
+	required, err := parseAction(action)
+	if err != nil {
+		d.Err = err
+		return d
+	}
+
+	if _, _, _, err := relation(i.Actor, r.Name, t); err != nil {
+		d.Err = errors.New(400, errors.E_access_deny, "actor can't impersonate: "+err.Error())
+		return d
+	}
+
+	actorperm := boundPerm(i.Actor, r.Name, int64(uint32(r.Get(i.Actor.GetPerm()))))
+	if err := checkPerm(required, actorperm, false, false, false); err != nil {
+		d.Err = errors.New(400, errors.E_access_deny, "actor can't impersonate: "+err.Error())
+		return d
+	}
+
+	if err := checkTarget(r.Name, required, int64(uint32(r.Get(i.Target.GetPerm()))), i.Target, t); err != nil {
+		d.Err = errors.New(400, errors.E_access_deny, "target: "+err.Error())
+		return d
+	}
+
+	d.Allowed = true
+	return d
+}
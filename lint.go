@@ -0,0 +1,205 @@
+package perm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/subiz/header/common"
+)
+
+// Issue is a problem found by the policy linter
+type Issue struct {
+	Source  string // where the problem is, e.g.: Base.Agent, role owner, scope agent
+	Message string
+}
+
+func (i Issue) String() string { return i.Source + ": " + i.Message }
+
+// Lint checks Base, the predefined roles and the scopes for common mistakes
+func Lint() []Issue {
+	p := CurrentPolicy()
+	base := p.Base()
+	issues := LintBase(base)
+	for _, name := range RoleNames() {
+		role, _ := Role(name)
+		issues = append(issues, LintRole(name, role, base)...)
+	}
+
+	entries := make(map[string]string, len(p.defs))
+	for name, def := range p.defs {
+		entries[name] = def.Entries
+	}
+	issues = append(issues, LintScopes(entries)...)
+	return append(issues, LintScopeDefs(p.defs)...)
+}
+
+// LintBase reports resources missing from base and suspicious combinations
+func LintBase(base *common.Permission) []Issue {
+	issues := make([]Issue, 0)
+	for _, r := range Resources {
+		source := "Base." + r.Name
+		num := r.Get(base)
+		if num == 0 {
+			issues = append(issues, Issue{source, "missing, no one can access this resource"})
+			continue
+		}
+		issues = append(issues, lintSuspicious(source, num)...)
+	}
+	return issues
+}
+
+// LintRole reports actions granted by role p but not allowed by base, and
+// suspicious combinations
+func LintRole(name string, p, base *common.Permission) []Issue {
+	issues := make([]Issue, 0)
+	source := "role " + name
+	for _, c := range Diff(base, p) {
+		if c.Added != 0 {
+			issues = append(issues, Issue{source, fmt.Sprintf("%s %s:%s exceeds Base", c.Resource, c.Level, actionLetters(c.Added))})
+		}
+	}
+
+	for _, r := range Resources {
+		issues = append(issues, lintSuspicious(source+" "+r.SnakeName, r.Get(p))...)
+	}
+	return issues
+}
+
+// LintScopes reports scope entries that reference unknown resources or
+// unknown actions. A resource prefixed with other_ refers to resources owned
+// by other agents, so it must match a permission field without the prefix.
+// Patterns must match at least one field, sub-resources must belong to one
+func LintScopes(scopes map[string]string) []Issue {
+	issues := make([]Issue, 0)
+
+	names := make([]string, 0, len(scopes))
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		source := "scope " + name
+		for _, item := range strings.Fields(scopes[name]) {
+			split := strings.Split(item, ":")
+			if len(split) != 2 {
+				issues = append(issues, Issue{source, fmt.Sprintf("malformed entry %q", item)})
+				continue
+			}
+
+			if !validScopeResource(split[0]) {
+				issues = append(issues, Issue{source, fmt.Sprintf("malformed resource %q", split[0])})
+			} else if !scopeResourceKnown(split[0]) {
+				issues = append(issues, Issue{source, fmt.Sprintf("%q does not match any permission field", split[0])})
+			}
+
+			for _, a := range split[1] {
+				if !strings.ContainsRune("rwep", a) {
+					issues = append(issues, Issue{source, fmt.Sprintf("unknown action %q in %q", a, item)})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// LintScopeDefs reports scopes which consent screens can't present: those
+// without title or description and those less sensitive than a parent
+func LintScopeDefs(defs map[string]ScopeDef) []Issue {
+	issues := make([]Issue, 0)
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// consent screens can't tell apart scopes described the same way
+	described := make(map[string]string)
+	for _, name := range names {
+		source := "scope " + name
+		def := defs[name]
+		if def.Title == "" {
+			issues = append(issues, Issue{source, "missing title"})
+		}
+
+		if def.Description == "" {
+			issues = append(issues, Issue{source, "missing description"})
+		} else if other, ok := described[def.Description]; ok {
+			issues = append(issues, Issue{source, "same description as scope " + other})
+		} else {
+			described[def.Description] = name
+		}
+
+		for _, parent := range def.Parents {
+			if p, ok := defs[parent]; ok && p.Sensitivity > def.Sensitivity {
+				issues = append(issues, Issue{source, fmt.Sprintf("sensitivity %s is lower than %s of parent %s", def.Sensitivity, p.Sensitivity, parent)})
+			}
+		}
+	}
+	return issues
+}
+
+// scopeResourceKnown reports whether the scope entry resource, a pattern or
+// a sub-resource, matches a permission field
+func scopeResourceKnown(resource string) bool {
+	for _, r := range Resources {
+		for _, name := range []string{r.SnakeName, "other_" + r.SnakeName} {
+			if covers(resource, name) || covers(name, resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LintPermString reports the parts of p which ToPerm silently ignores:
+// unknown levels and unknown action letters
+func LintPermString(p string) []string {
+	problems := make([]string, 0)
+	for _, perm := range strings.Split(strings.TrimSpace(p), " ") {
+		perm = strings.TrimSpace(strings.ToLower(perm))
+		if perm == "" {
+			continue
+		}
+
+		if len(perm) < 2 || perm[1] != ':' {
+			problems = append(problems, fmt.Sprintf("malformed token %q", perm))
+			continue
+		}
+
+		if !strings.ContainsRune("ugas", rune(perm[0])) {
+			// an unknown level without any action, such as o:----, loses nothing
+			if strings.Trim(perm[2:], "-") != "" {
+				problems = append(problems, fmt.Sprintf("unknown level %q in %q, it is ignored", perm[0], perm))
+			}
+			continue
+		}
+
+		for _, a := range perm[2:] {
+			if !strings.ContainsRune("crud-"+extraActions, a) {
+				problems = append(problems, fmt.Sprintf("unknown action %q in %q", a, perm))
+			}
+		}
+	}
+	return problems
+}
+
+// lintSuspicious reports levels which allow deleting or updating a resource
+// without reading it
+func lintSuspicious(source string, num int32) []Issue {
+	issues := make([]Issue, 0)
+	for _, level := range []string{"u", "g", "a", "s"} {
+		p := getPerm(level, int64(uint32(num)))
+		if p&READPERM != 0 {
+			continue
+		}
+
+		if p&DELETEPERM != 0 {
+			issues = append(issues, Issue{source, fmt.Sprintf("%s:%s allows delete without read", level, intPermToStr(p))})
+		} else if p&UPDATEPERM != 0 {
+			issues = append(issues, Issue{source, fmt.Sprintf("%s:%s allows update without read", level, intPermToStr(p))})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,145 @@
+package perm
+
+import (
+	"github.com/subiz/errors"
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
+)
+
+// OwnershipResolver decides whether the caller owns a resource, which is what
+// grants the caller the u: (own resource) permission. resource is the Go name
+// of the permission field, e.g. Conversation, and owners are the ids passed
+// to the Check functions, e.g. the agent who created the resource, the agents
+// a conversation is assigned to, or the groups owning it
+type OwnershipResolver interface {
+	IsOwner(cred *common.Credential, resource, accid string, owners []string) bool
+}
+
+// OwnershipFunc is an adapter to use ordinary functions as OwnershipResolver
+type OwnershipFunc func(cred *common.Credential, resource, accid string, owners []string) bool
+
+// IsOwner calls f(cred, resource, accid, owners)
+func (f OwnershipFunc) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	return f(cred, resource, accid, owners)
+}
+
+// IssuerOwnership is the default resolver, the caller owns the resource when
+// its issuer is one of the owners
+type IssuerOwnership struct{}
+
+// IsOwner implements OwnershipResolver
+func (IssuerOwnership) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	return contains(cred.GetIssuer(), owners)
+}
+
+// GroupOwnership lets resources be owned by agent groups, the caller owns the
+// resource when one of its groups is one of the owners
+type GroupOwnership struct {
+	// Groups returns ids of the groups agent agid belongs to, a nil Groups
+	// makes the caller own nothing
+	Groups func(accid, agid string) []string
+}
+
+// IsOwner implements OwnershipResolver
+func (g GroupOwnership) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	if g.Groups == nil {
+		return false
+	}
+
+	for _, group := range g.Groups(accid, cred.GetIssuer()) {
+		if contains(group, owners) {
+			return true
+		}
+	}
+	return false
+}
+
+// DelegatedOwnership lets owners delegate their resources to other agents,
+// the caller owns the resource when an owner has delegated to it
+type DelegatedOwnership struct {
+	// Delegates returns ids of the agents owner has delegated to, a nil
+	// Delegates makes the caller own nothing
+	Delegates func(accid, owner string) []string
+}
+
+// IsOwner implements OwnershipResolver
+func (d DelegatedOwnership) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	if d.Delegates == nil {
+		return false
+	}
+
+	for _, owner := range owners {
+		if contains(cred.GetIssuer(), d.Delegates(accid, owner)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnyOwnership combines resolvers, the caller owns the resource when any of
+// the resolvers says so
+type AnyOwnership []OwnershipResolver
+
+// IsOwner implements OwnershipResolver
+func (a AnyOwnership) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	for _, r := range a {
+		if r != nil && r.IsOwner(cred, resource, accid, owners) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceOwnership picks the resolver by resource, e.g. assigned-to lists
+// for conversations only. Resources without a resolver use Default, or
+// IssuerOwnership when Default is nil
+type ResourceOwnership struct {
+	Resolvers map[string]OwnershipResolver // Go name of the resource => resolver
+	Default   OwnershipResolver
+}
+
+// IsOwner implements OwnershipResolver
+func (r ResourceOwnership) IsOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	if resolver := r.Resolvers[resource]; resolver != nil {
+		return resolver.IsOwner(cred, resource, accid, owners)
+	}
+
+	if r.Default != nil {
+		return r.Default.IsOwner(cred, resource, accid, owners)
+	}
+	return IssuerOwnership{}.IsOwner(cred, resource, accid, owners)
+}
+
+var ownership = newHolder(OwnershipResolver(IssuerOwnership{}))
+
+// SetOwnershipResolver replaces the resolver used by the Check functions, it
+// is safe to call while checks are running. A nil r restores IssuerOwnership
+func SetOwnershipResolver(r OwnershipResolver) {
+	if r == nil {
+		r = IssuerOwnership{}
+	}
+	ownership.store(r)
+}
+
+// isOwner reports whether the caller owns the instance of resource owned by
+// owners, using the current ownership resolver
+func isOwner(cred *common.Credential, resource, accid string, owners []string) bool {
+	return ownership.load().(OwnershipResolver).IsOwner(cred, resource, accid, owners)
+}
+
+// Target describes the resource a Check function is evaluated against. Its
+// owners are resolved with the current OwnershipResolver and its attributes
+// are what the conditions attached to the checked action are evaluated
+// against
+type Target = core.Target
+
+// checkTarget checks whether cred may do action required on t, an instance
+// of resource, with permission callerperm on it. Once the permission bits
+// allow the action, the conditions attached to it must hold as well
+func checkTarget(resource string, required int32, callerperm int64, cred *common.Credential, t Target) error {
+	err := core.Check(fixedPrincipal{credentialPrincipal{cred}, callerperm}, resource, required, t)
+	if err == core.ErrDenied {
+		return errors.New(400, errors.E_access_deny, err.Error())
+	}
+	return err
+}
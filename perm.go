@@ -3,72 +3,35 @@ package perm
 //go:generate ./gen.sh
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 
 	"github.com/subiz/errors"
 	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
 )
 
-func getPerm(r string, num int32) int32 {
-	if r == "u" {
-		num &= 0x000F
-	} else if r == "a" {
-		num &= 0x00F0
-		num = num >> 4
-	} else if r == "s" {
-		num &= 0x0F00
-		num = num >> 8
-	} else {
-		num = 0
-	}
-	return num
-}
-
-// required: the required permission
-func checkPerm(required, callerperm int32, ismine, sameaccount bool) error {
-	// check super perm first
-	if required&getPerm("s", callerperm) == required {
-		return nil
-	}
+// getPerm returns the permission of level r in num, the lower 4 bits are
+// create, read, update, delete and the upper 4 bits are the extra actions.
+// num is either in V1 or V2 encoding
+func getPerm(r string, num int64) int32 { return core.GetPerm(r, num) }
 
-	if !sameaccount {
-		return errors.New(400, errors.E_access_deny, "not enought permission")
-	}
-
-	// check my resource permission
-	if ismine {
-		if required&getPerm("u", callerperm) == required {
-			return nil
-		}
-	}
+// makePerm is the reverse of getPerm, it places permission p at level r
+func makePerm(r string, p int32) int32 { return core.MakePerm(r, p) }
 
-	if required&getPerm("a", callerperm) == required {
-		return nil
+// required: the required permission
+// callerperm: the caller permission, in either V1 or V2 encoding
+// ismine: the caller owns the resource
+// ingroup: the resource is owned by one of the caller's agent groups
+func checkPerm(required int32, callerperm int64, ismine, ingroup, sameaccount bool) error {
+	if err := core.CheckPerm(required, callerperm, ismine, ingroup, sameaccount); err != nil {
+		return errors.New(400, errors.E_access_deny, err.Error())
 	}
-
-	return errors.New(400, errors.E_access_deny, "not enough permission")
+	return nil
 }
 
-func strPermToInt(p string) int32 {
-	out := int32(0)
-	if strings.Contains(p, "c") {
-		out |= 8
-	}
-
-	if strings.Contains(p, "r") {
-		out |= 4
-	}
-
-	if strings.Contains(p, "u") {
-		out |= 2
-	}
-
-	if strings.Contains(p, "d") {
-		out |= 1
-	}
-	return out
-}
+func strPermToInt(p string) int32 { return core.ParseActions(p, extraActions) }
 
 // Intersect returns a strongest permission which both a and b contains
 func Intersect(a, b *common.Permission) *common.Permission {
@@ -136,35 +99,55 @@ func Merge(a, b *common.Permission) *common.Permission {
 	return ret
 }
 
-// ToPerm converts permission in string representation to integer representation
-// examples:
-//   ToPerm("u:-ru-")   0x6
-//   ToPerm("u:r u:u")  0x6
-func ToPerm(p string) int32 {
-	rawperms := strings.Split(strings.TrimSpace(p), " ")
-	um, am, sm := "", "", ""
-	for _, perm := range rawperms {
-		perm = strings.TrimSpace(strings.ToLower(perm))
-		if len(perm) < 2 {
-			continue
-		}
+func intPermToStr(num int32) string {
+	out := []byte("----")
+	if num&8 != 0 {
+		out[0] = 'c'
+	}
 
-		if perm[0] == 'u' {
-			um += perm[1:]
-		} else if perm[0] == 'a' {
-			am += perm[1:]
-		} else if perm[0] == 's' {
-			sm += perm[1:]
-		} else {
-			continue
+	if num&4 != 0 {
+		out[1] = 'r'
+	}
+
+	if num&2 != 0 {
+		out[2] = 'u'
+	}
+
+	if num&1 != 0 {
+		out[3] = 'd'
+	}
+
+	// extra actions are only printed when granted
+	for i, l := range extraActions {
+		if num&(0x10<<uint(i)) != 0 {
+			out = append(out, byte(l))
 		}
 	}
-	return strPermToInt(um) | strPermToInt(am)<<4 | strPermToInt(sm)<<8
+	return string(out)
 }
 
+// FormatPerm converts permission in integer representation to string
+// representation, it is the reverse of ToPerm. The g: level is only printed
+// when it grants something
+// examples:
+//   FormatPerm(0x6)        "u:-ru- a:---- s:----"
+//   FormatPerm(0x406)      "u:-ru- a:---- s:-r--"
+//   FormatPerm(0x4000406)  "u:-ru- g:-r-- a:---- s:-r--"
+func FormatPerm(num int32) string {
+	return FormatPerm64(int64(uint32(num)))
+}
+
+// ToPerm converts permission in string representation to integer representation
+// examples:
+//   ToPerm("u:-ru-")   0x6
+//   ToPerm("u:r u:u")  0x6
+func ToPerm(p string) int32 { return core.ParsePerm(p, extraActions) }
+
 // Base is the biggest possible permission that is valid
 // it is often used with IntersectPermission method to correct mal-granted
-// permissions
+// permissions.
+// Base is a read-only snapshot of the default policy, changing it affects
+// nothing, use CurrentPolicy().Base() and SetBase instead
 var Base = common.Permission{
 	Account:               ToPerm("o:---- u:---- a:cru- s:cru-"),
 	Agent:                 ToPerm("o:-r-- u:-ru- a:crud s:-r-d"),
@@ -174,7 +157,7 @@ var Base = common.Permission{
 	Segmentation:          ToPerm("o:---- u:crud a:crud s:-r--"),
 	Client:                ToPerm("o:---- u:---- a:---- s:-r--"),
 	Rule:                  ToPerm("o:---- u:---- a:crud s:-r--"),
-	Conversation:          ToPerm("o:---- u:cru- a:-ru- s:cr--"),
+	Conversation:          ToPerm("o:---- u:cru- g:-ru- a:-ru- s:cr--"),
 	Integration:           ToPerm("o:---- u:---- a:crud s:cr--"),
 	CannedResponse:        ToPerm("o:---- u:crud a:crud s:cr--"),
 	Tag:                   ToPerm("o:---- u:---- a:crud s:cr--"),
@@ -188,7 +171,7 @@ var Base = common.Permission{
 	Bill:                  ToPerm("o:---- u:---- a:-r-- s:cru-"),
 	PaymentLog:            ToPerm("o:---- u:---- a:-r-- s:-r--"),
 	PaymentComment:        ToPerm("o:---- u:---- a:---- s:crud"),
-	User:                  ToPerm("o:---- u:crud a:crud s:cru-"),
+	User:                  ToPerm("o:---- u:crud g:-ru- a:crud s:cru-"),
 	Automation:            ToPerm("o:-r-- u:---- a:crud s:cr--"),
 	Ping:                  ToPerm("o:---- u:crud a:crud s:----"),
 	Attribute:             ToPerm("o:---- u:---- a:crud s:-r--"),
@@ -204,15 +187,18 @@ var Base = common.Permission{
 	Referral:              ToPerm("o:---- u:crud a:---- s:crud"),
 }
 
-// MakeBase returns copy of Base permission
-func MakeBase() common.Permission { return Base }
-
-var Scopes = makeScopeMap()
-
-func makeScopeMap() map[string]string {
-	// scope => permission
-	var m = map[string]string{
-		"agent": `
+// MakeBase returns a copy of Base of the current policy, the biggest possible
+// permission that is valid. It is often used with IntersectPermission to
+// correct mal-granted permissions
+func MakeBase() common.Permission { return *CurrentPolicy().base }
+
+// scopeGraph declares every scope with the scopes it inherits
+var scopeGraph = map[string]ScopeDef{
+	"agent": {
+		Title:       "Act as an agent",
+		Description: "Read and reply to your conversations, manage your message templates and the users you talk to, and read the account's settings.",
+		Sensitivity: SensitivityMedium,
+		Entries: `
 conversation:rw
 permission:r
 agent_group:r
@@ -228,10 +214,25 @@ subscription:r
 invoice:r
 user:rw
 attribute:r`,
-		"view_other_convos": "other_conversation:r",
-		"export_user":       "user:e", // export
-	}
-	m["account_setting"] = m["agent"] + `
+	},
+	"view_other_convos": {
+		Title:       "View other agents' conversations",
+		Description: "Read every conversation of the account, not only yours.",
+		Sensitivity: SensitivityMedium,
+		Entries:     "other_conversation:r",
+	},
+	"export_user": {
+		Title:       "Export users",
+		Description: "Export the users of the account, including their contact details.",
+		Sensitivity: SensitivityHigh,
+		Entries:     "user:e", // export
+	},
+	"account_setting": {
+		Title:       "Manage account settings",
+		Description: "Everything an agent can do, plus changing permissions, agent groups, rules, integrations, tags, whitelists, widgets and attributes.",
+		Sensitivity: SensitivityHigh,
+		Parents:     []string{"agent"},
+		Entries: `
 permission:rw
 agent_group:w
 rule:w
@@ -242,83 +243,81 @@ whitelist_ip:w
 whitelist_user:w
 whitelist_domain:w
 widget:w
-attribute:w`
-	m["account_manage"] = m["account_setting"] + " subscription:rw payment_method:rw"
-	m["owner"] = m["account_manage"]
-	m["all"] = m["account_manage"]
-	return m
+attribute:w`,
+	},
+	"account_manage": {
+		Title:       "Manage the account",
+		Description: "Everything of account settings, plus managing the subscription and payment methods.",
+		Sensitivity: SensitivityHigh,
+		Parents:     []string{"account_setting"},
+		Entries:     "subscription:rw payment_method:rw",
+	},
+	"owner": {
+		Title:       "Full access as the owner",
+		Description: "Act on behalf of the owner of the account: everything the owner can do, including billing.",
+		Sensitivity: SensitivityHigh,
+		Parents:     []string{"account_manage"},
+	},
+	"all": {
+		Title:       "Full access",
+		Description: "The same access as managing the account: everything an agent can do, the account settings, the subscription and payment methods. It doesn't include other agents' conversations nor exporting users. Meant for trusted first-party integrations.",
+		Sensitivity: SensitivityHigh,
+		Parents:     []string{"account_manage"},
+	},
 }
 
-func prettyPerm(perm string) string {
-	perms := strings.FieldsFunc(perm, func(r rune) bool {
-		return r == ' ' || r == ';' || r == ',' || r == '\n'
-	})
-	permM := make(map[string]string)
-	for _, p := range perms {
-		p = strings.TrimSpace(p)
-
-		psplit := strings.Split(p, ":")
-		if len(psplit) != 2 {
-			continue
-		}
-		permM[psplit[0]] += psplit[1]
+// Scopes maps the name of each scope to the actions it allows, including
+// the inherited ones.
+// Scopes is a read-only snapshot of the default policy, changing it affects
+// nothing, use CurrentPolicy().Scopes() and RegisterScope instead
+var Scopes = defaultPolicy.Scopes()
+
+// Scopes returns a copy of every scope of the policy
+func (p *Policy) Scopes() map[string]ScopeSet {
+	m := make(map[string]ScopeSet, len(p.scopes))
+	for name, set := range p.scopes {
+		m[name] = set.Union(nil)
 	}
+	return m
+}
 
-	out := ""
-	for k, v := range permM {
-		ppp := ""
-		if strings.Contains(v, "w") {
-			ppp += "w"
-		}
-		if strings.Contains(v, "r") {
-			ppp += "r"
-		}
-		if strings.Contains(v, "e") {
-			ppp += "e"
-		}
-		if strings.Contains(v, "p") {
-			ppp += "p"
-		}
-		if ppp != "" {
-			out += strings.TrimSpace(k) + ":" + ppp + " "
-		}
-	}
-	return strings.TrimSpace(out)
+func prettyPerm(perm string) string {
+	return ParseScopeSet(perm).String()
 }
 
 // []string{"all", "agent"}, "conversation:r tag:wr" => true
 // []string{"agent"}, "tag:wr" => false
+// Scopes are compiled once, Access doesn't allocate as long as the policy
+// has at most 64 scopes. Beyond that a scope no longer fits in the bit mask
+// of the compiled form and the scopes are evaluated as ScopeSets, which
+// allocates
 func Access(scopes []string, perm string) bool {
-	// make availabe perm map by joining all permision in scopes
-	availableperm := make(map[string]string) // {"conversation" => "cr", "user" => "u"}
-	joinperm := ""
-	for _, scope := range scopes {
-		joinperm += " " + Scopes[strings.TrimSpace(scope)]
-	}
+	return CurrentPolicy().access(scopes, perm)
+}
 
-	joinperm = prettyPerm(joinperm)
-	joinpermsplit := strings.Split(joinperm, " ")
-	for _, joinpermitem := range joinpermsplit {
-		joinpermitemsplit := strings.Split(joinpermitem, ":")
-		if len(joinpermitemsplit) != 2 {
+// AccessCheck is Access with details. It returns the resource:action pairs
+// of perm which scopes don't allow, e.g. [payment_method:w], and fails on
+// unknown scopes and malformed entries in perm
+func AccessCheck(scopes []string, perm string) ([]string, error) {
+	p := CurrentPolicy()
+	available := ScopeSet{}
+	unknown := make([]string, 0)
+	for _, scope := range scopes {
+		set, ok := p.scopes[strings.TrimSpace(scope)]
+		if !ok {
+			unknown = append(unknown, scope)
 			continue
 		}
-		availableperm[joinpermitemsplit[0]] = joinpermitemsplit[1]
+		available = available.Union(set)
 	}
 
-	perm = prettyPerm(perm)
-	perms := strings.Split(perm, " ")
-	for _, p := range perms {
-		ps := strings.Split(p, ":") // conversation:rw
-		if len(ps) != 2 {
-			continue
-		}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown scopes %q", unknown)
+	}
 
-		for _, p := range ps[1] {
-			if !strings.Contains(availableperm[ps[0]], string(p)) {
-				return false
-			}
-		}
+	required, err := ParseScopeSetStrict(perm)
+	if err != nil {
+		return nil, err
 	}
-	return true
+	return required.Difference(available).Pairs(), nil
 }
@@ -3,10 +3,15 @@ package perm
 import (
 	"encoding/hex"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
 func TestAccess(t *testing.T) {
@@ -32,6 +37,102 @@ func TestAccess(t *testing.T) {
 	}
 }
 
+func TestAccessCheck(t *testing.T) {
+	tcs := []struct {
+		desc    string
+		scopes  []string
+		perm    string
+		missing string
+		err     string
+	}{
+		{"allowed", []string{"account_setting"}, "message_template:rw", "", ""},
+		{"missing", []string{"agent"}, "payment_method:rw tag:rw invoice:r", "payment_method:w payment_method:r tag:w", ""},
+		{"union", []string{"view_other_convos", "agent"}, "other_conversation:r conversation:rw", "", ""},
+		{"unknown scope", []string{"agent", "acount_setting", "ownr"}, "tag:r", "", `unknown scopes ["acount_setting" "ownr"]`},
+		{"malformed", []string{"agent"}, "tag:r rule", "", `malformed entry "rule", expect resource:actions`},
+		{"empty actions", []string{"agent"}, "tag:", "", `malformed entry "tag:", expect resource:actions`},
+		{"unknown action", []string{"agent"}, "tag:rx", "", `unknown action 'x' in "tag:rx"`},
+	}
+
+	for _, tc := range tcs {
+		missing, err := AccessCheck(tc.scopes, tc.perm)
+		if tc.err != "" {
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("[%s] expect error %s, got %v", tc.desc, tc.err, err)
+			}
+			continue
+		}
+
+		if err != nil || strings.Join(missing, " ") != tc.missing {
+			t.Errorf("[%s] expect missing %q, got %q, %v", tc.desc, tc.missing, missing, err)
+		}
+
+		if (len(missing) == 0) != Access(tc.scopes, tc.perm) {
+			t.Errorf("[%s] expect AccessCheck to agree with Access", tc.desc)
+		}
+	}
+}
+
+func TestAccessCompiled(t *testing.T) {
+	sets := map[string]ScopeSet{
+		"a": ParseScopeSet("conversation:w whitelist_*:r *:e"),
+		"b": ParseScopeSet("conversation.message:r tag:rw"),
+	}
+	c, err := compileScopeSets(sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the compiled evaluation must agree with ScopeSet
+	perms := []string{
+		"conversation:w", "conversation:r", "conversation.message:wr", "conversation.*:w",
+		"conversation*:w", "whitelist_ip:r", "whitelist_*:r", "whitelist*:r", "tag:rwe",
+		"other_tag:e", "*:e", "*:r", "tag:r,conversation:w; user:e\nrule:r", "tag:r:w", "tag:",
+	}
+	for _, scopes := range [][]string{{}, {"a"}, {"b"}, {"a", " b "}, {"a", "c"}} {
+		available := ScopeSet{}
+		for _, scope := range scopes {
+			available = available.Union(sets[strings.TrimSpace(scope)])
+		}
+
+		for _, perm := range perms {
+			expect := ParseScopeSet(perm).SubsetOf(available)
+			if out := c.access(scopes, perm); out != expect {
+				t.Errorf("[%v %s] expect %v, got %v", scopes, perm, expect, out)
+			}
+		}
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Access([]string{"owner", "account_setting"}, "payment_method:rw conversation:r tag:wr")
+	})
+	if allocs != 0 {
+		t.Errorf("expect no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkAccess(b *testing.B) {
+	scopes := []string{"agent", "view_other_convos"}
+	for i := 0; i < b.N; i++ {
+		Access(scopes, "conversation:rw other_conversation:r tag:r user:rw attribute:r")
+	}
+}
+
+// BenchmarkAccessScopeSet evaluates like Access did before scopes were
+// compiled, for comparison
+func BenchmarkAccessScopeSet(b *testing.B) {
+	scopes := []string{"agent", "view_other_convos"}
+	sets := CurrentPolicy().Scopes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		available := ScopeSet{}
+		for _, scope := range scopes {
+			available = available.Union(sets[scope])
+		}
+		ParseScopeSet("conversation:rw other_conversation:r tag:r user:rw attribute:r").SubsetOf(available)
+	}
+}
+
 func TestCheckToPerm(t *testing.T) {
 	tcs := []struct {
 		desc   string
@@ -44,6 +145,7 @@ func TestCheckToPerm(t *testing.T) {
 		{"4", "u:ur", 0x6},
 		{"5", "u:ur s:r", 0x406},
 		{"6", "u:crud s:crud a:crud", 0xFFF},
+		{"7", "u:e a:-r--e", 0x11040},
 	}
 
 	for _, tc := range tcs {
@@ -59,9 +161,9 @@ func TestPrettyPerm(t *testing.T) {
 		perm   string
 		pretty string
 	}{
-		{"user:r   tag:r w    conversation: r tag:e", "user:r tag:re"},
-		{makeScopeMap()["agent"], "rule:r integration:r message_template:wr tag:r user:wr attribute:r permission:r agent_group:r whitelist_user:r whitelist_domain:r widget:r invoice:r conversation:wr whitelist_ip:r subscription:r"},
-		{makeScopeMap()["all"], "attribute:wr payment_method:wr conversation:wr permission:wr tag:wr whitelist_ip:wr other_message_template:wr user:wr agent_group:wr rule:wr integration:wr widget:wr subscription:wr invoice:r message_template:wr whitelist_user:wr whitelist_domain:wr"},
+		{"user:r   tag:r w    conversation: r tag:e", "tag:re user:r"},
+		{Scopes["agent"].String(), "agent_group:r attribute:r conversation:wr integration:r invoice:r message_template:wr permission:r rule:r subscription:r tag:r user:wr whitelist_domain:r whitelist_ip:r whitelist_user:r widget:r"},
+		{Scopes["all"].String(), "agent_group:wr attribute:wr conversation:wr integration:wr invoice:r message_template:wr other_message_template:wr payment_method:wr permission:wr rule:wr subscription:wr tag:wr user:wr whitelist_domain:wr whitelist_ip:wr whitelist_user:wr widget:wr"},
 	}
 
 	for _, tc := range tcs {
@@ -72,6 +174,178 @@ func TestPrettyPerm(t *testing.T) {
 	}
 }
 
+func TestScopeSet(t *testing.T) {
+	a := ParseScopeSet("conversation:rw tag:r, user:e;user:r")
+	b := ParseScopeSet("conversation:r\ntag:rw invoice:r")
+
+	tcs := []struct {
+		desc   string
+		set    ScopeSet
+		expect string
+	}{
+		{"parse", a, "conversation:wr tag:r user:re"},
+		{"union", a.Union(b), "conversation:wr invoice:r tag:wr user:re"},
+		{"intersect", a.Intersect(b), "conversation:r tag:r"},
+		{"difference", a.Difference(b), "conversation:w user:re"},
+		{"empty", ParseScopeSet("tag: conversation:x"), ""},
+	}
+
+	for _, tc := range tcs {
+		if out := tc.set.String(); out != tc.expect {
+			t.Errorf("[%s] expect %q, got %q", tc.desc, tc.expect, out)
+		}
+	}
+
+	if !a.Intersect(b).SubsetOf(a) || a.SubsetOf(b) || !a.SubsetOf(a.Union(b)) {
+		t.Error("wrong subset")
+	}
+
+	if !a.Equal(ParseScopeSet(a.String())) || a.Equal(b) {
+		t.Error("wrong equal")
+	}
+}
+
+func TestScopeWildcard(t *testing.T) {
+	set := ParseScopeSet("*:r conversation:w conversation.message:e whitelist_*:w whitelist_ip:p")
+	tcs := []struct {
+		perm   string
+		expect bool
+	}{
+		{"tag:r other_conversation:r", true},
+		{"tag:w", false},
+		{"conversation.message:wre conversation.note:w", true},
+		{"conversation:e", false},
+		{"whitelist_user:wr whitelist_*:w whitelist_ip:p", true},
+		{"whitelist_user:p", false},
+		{"whitelist*:w", false},
+		{"*:r", true},
+		{"*:w", false},
+	}
+
+	for _, tc := range tcs {
+		if out := ParseScopeSet(tc.perm).SubsetOf(set); out != tc.expect {
+			t.Errorf("[%s] expect %v, got %v", tc.perm, tc.expect, out)
+		}
+	}
+
+	matches := []struct {
+		resource string
+		action   rune
+		expect   string
+	}{
+		{"conversation.message", 'e', "conversation.message"},
+		{"conversation.message", 'w', "conversation"},
+		{"conversation.message", 'r', "*"},
+		{"whitelist_ip", 'p', "whitelist_ip"},
+		{"whitelist_ip", 'w', "whitelist_*"},
+		{"tag", 'w', ""},
+	}
+
+	for _, m := range matches {
+		if out := set.Match(m.resource, m.action); out != m.expect {
+			t.Errorf("[%s:%c] expect %q, got %q", m.resource, m.action, m.expect, out)
+		}
+	}
+
+	if out := set.Intersect(ParseScopeSet("tag:rw whitelist_domain:rw")).String(); out != "tag:r whitelist_domain:wr" {
+		t.Errorf("expect tag:r whitelist_domain:wr, got %s", out)
+	}
+
+	for _, bad := range []string{"whit*list:r", "conversation..message:r", "Tag:r", "*.x:r"} {
+		if _, err := ParseScopeSetStrict(bad); err == nil {
+			t.Errorf("[%s] expect malformed resource", bad)
+		}
+	}
+
+	// a pattern evaluates like the list of resources it covers
+	defer SetPolicy(CurrentPolicy())
+	if err := RegisterScope("test_pattern", ScopeDef{Entries: "whitelist_*:r"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterScope("test_list", ScopeDef{Entries: "whitelist_ip:r whitelist_user:r whitelist_domain:r"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pattern, err := CompileScopes([]string{"test_pattern"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, _ := CompileScopes([]string{"test_list"})
+	if !proto.Equal(pattern, list) {
+		t.Errorf("expect %s, got %s", FormatPermission(list), FormatPermission(pattern))
+	}
+
+	for _, r := range Resources {
+		for _, name := range []string{r.SnakeName, "other_" + r.SnakeName} {
+			for _, action := range []string{"r", "w"} {
+				perm := name + ":" + action
+				if Access([]string{"test_pattern"}, perm) != Access([]string{"test_list"}, perm) {
+					t.Errorf("[%s] expect %v, got %v", perm, Access([]string{"test_list"}, perm), Access([]string{"test_pattern"}, perm))
+				}
+			}
+		}
+	}
+}
+
+func TestScopeGraph(t *testing.T) {
+	// inherited scopes hold all entries of their ancestors
+	for _, pair := range [][2]string{{"agent", "account_setting"}, {"account_setting", "account_manage"}, {"account_manage", "owner"}} {
+		if !CurrentPolicy().Scopes()[pair[0]].SubsetOf(CurrentPolicy().Scopes()[pair[1]]) {
+			t.Errorf("expect %s within %s", pair[0], pair[1])
+		}
+	}
+
+	if !CurrentPolicy().Scopes()["owner"].Equal(CurrentPolicy().Scopes()["all"]) || !CurrentPolicy().Scopes()["owner"].Equal(CurrentPolicy().Scopes()["account_manage"]) {
+		t.Error("expect owner, all and account_manage to be the same")
+	}
+
+	including, err := ScopesIncluding("account_setting")
+	if err != nil || strings.Join(including, " ") != "account_manage all owner" {
+		t.Errorf("expect account_manage all owner, got %v, %v", including, err)
+	}
+
+	if _, err := ScopesIncluding("nope"); err == nil {
+		t.Error("expect undefined scope error")
+	}
+
+	if out := strings.Join(ScopesGranting("subscription:w"), " "); out != "account_manage all owner" {
+		t.Errorf("expect account_manage all owner, got %s", out)
+	}
+
+	tcs := []struct {
+		desc string
+		defs map[string]ScopeDef
+		err  string
+	}{
+		{"undefined", map[string]ScopeDef{"a": {Parents: []string{"b"}}}, `scope a: undefined parent "b"`},
+		{"self", map[string]ScopeDef{"a": {Parents: []string{"a"}}}, "scope cycle: a -> a"},
+		{"cycle", map[string]ScopeDef{
+			"a": {Parents: []string{"b"}},
+			"b": {Parents: []string{"c"}},
+			"c": {Parents: []string{"a"}, Entries: "tag:r"},
+		}, "scope cycle: a -> b -> c -> a"},
+	}
+
+	for _, tc := range tcs {
+		if _, err := ResolveScopes(tc.defs); err == nil || err.Error() != tc.err {
+			t.Errorf("[%s] expect %s, got %v", tc.desc, tc.err, err)
+		}
+	}
+
+	// diamond
+	m, err := ResolveScopes(map[string]ScopeDef{
+		"a": {Entries: "tag:r"},
+		"b": {Parents: []string{"a"}, Entries: "rule:r"},
+		"c": {Parents: []string{"a"}, Entries: "rule:w"},
+		"d": {Parents: []string{"b", "c"}},
+	})
+	if err != nil || m["d"].String() != "rule:wr tag:r" {
+		t.Errorf("expect rule:wr tag:r, got %v, %v", m["d"], err)
+	}
+}
+
 func TestIntersectPermission(t *testing.T) {
 	p := IntersectPermission(&common.Permission{
 		Widget: ToPerm("s:rud a:c u:r"),
@@ -376,3 +650,1098 @@ func TestAgentPerm(t *testing.T) {
 		fmt.Println("got account setting")
 	}
 }
+
+func TestFormatPerm(t *testing.T) {
+	tcs := []struct {
+		perm   int32
+		expect string
+	}{
+		{0x0, "u:---- a:---- s:----"},
+		{0x6, "u:-ru- a:---- s:----"},
+		{0x406, "u:-ru- a:---- s:-r--"},
+		{0xFFF, "u:crud a:crud s:crud"},
+		{0x101406, "u:-ru-e a:---- s:-r--e"},
+		{0x4000406, "u:-ru- g:-r-- a:---- s:-r--"},
+	}
+
+	for _, tc := range tcs {
+		out := FormatPerm(tc.perm)
+		if out != tc.expect {
+			t.Errorf("[%x] expect %s, got %s", tc.perm, tc.expect, out)
+		}
+
+		if ToPerm(out) != tc.perm {
+			t.Errorf("[%x] round trip got %x", tc.perm, ToPerm(out))
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	// Explain must always agree with checkPerm
+	for _, required := range []int32{CREATEPERM, READPERM, UPDATEPERM, DELETEPERM} {
+		for perm := int64(0); perm <= 0xFFF; perm += 0x3B {
+			callerperm := perm | (perm&0xF0)<<20
+			for _, ismine := range []bool{true, false} {
+				for _, ingroup := range []bool{true, false} {
+					for _, sameaccount := range []bool{true, false} {
+						err := checkPerm(required, callerperm, ismine, ingroup, sameaccount)
+						e := Explain(required, callerperm, ismine, ingroup, sameaccount)
+						if e.Allowed != (err == nil) {
+							t.Fatalf("[%x %x %v %v %v] expect allowed %v, got %v", required, callerperm, ismine, ingroup, sameaccount, err == nil, e.Allowed)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	e, err := ExplainCheck("agent_group", "read", &common.Credential{
+		AccountId: "ac1",
+		Issuer:    "ag1",
+		Perm:      &common.Permission{AgentGroup: ToPerm("u:r")},
+	}, "ac1", "ag1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !e.Allowed || len(e.Steps) != 4 {
+		t.Errorf("expect allowed at user level, got %s", e)
+	}
+
+	e, err = ExplainTarget("conversation", "u", &common.Credential{
+		AccountId: "ac1",
+		Issuer:    "ag2",
+		Perm:      &common.Permission{Conversation: ToPerm("u:cru- g:-ru-")},
+	}, Target{AccountId: "ac1", Owners: []string{"ag1"}, Groups: []string{"gr1"}, CallerGroups: []string{"gr2", "gr1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !e.Allowed || !e.InGroup || e.IsMine {
+		t.Errorf("expect allowed at group level, got %s", e)
+	}
+
+	if _, err := ExplainCheck("agent_groups", "read", nil, "ac1"); err == nil {
+		t.Error("expect unknown resource error")
+	}
+
+	if _, err := ExplainCheck("agent", "archive", nil, "ac1"); err == nil {
+		t.Error("expect unknown action error")
+	}
+
+	for _, action := range []string{"e", "E", "export", " Export"} {
+		if required, err := parseAction(action); err != nil || required != EXPORTPERM {
+			t.Errorf("[%s] expect export, got %x %v", action, required, err)
+		}
+	}
+
+	if required, err := parseAction("READ"); err != nil || required != READPERM {
+		t.Errorf("expect read, got %x %v", required, err)
+	}
+}
+
+func TestFormatPermission(t *testing.T) {
+	p := &common.Permission{
+		Agent:       ToPerm("u:-ru- a:-r--"),
+		WhitelistIp: ToPerm("a:crud s:-r--"),
+	}
+	expect := "agent: u:-ru- a:-r-- s:----\nwhitelist_ip: u:---- a:crud s:-r--\n"
+	out := FormatPermission(p)
+	if out != expect {
+		t.Errorf("expect %q, got %q", expect, out)
+	}
+
+	pp, err := ParsePermission("# comment\n" + out + "\nAgent: a:c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.Agent |= ToPerm("a:c")
+	if !equalPermission(p, pp) {
+		t.Errorf("expect %v, got %v", p, pp)
+	}
+
+	if _, err := ParsePermission("agents: u:r"); err == nil {
+		t.Error("expect unknown resource error")
+	}
+
+	if _, err := ParsePermission("u:r"); err == nil {
+		t.Error("expect unknown resource error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &common.Permission{
+		Agent:  ToPerm("u:-ru- a:-r--"),
+		Widget: ToPerm("a:crud"),
+	}
+	b := &common.Permission{
+		Agent:        ToPerm("u:-ru- a:cr-d s:r"),
+		Subscription: ToPerm("u:r"),
+	}
+
+	out := ""
+	for _, c := range Diff(a, b) {
+		out += c.String() + "\n"
+	}
+
+	// subscription is declared after widget
+	expect := "agent a: +cd\nagent s: +r\nsubscription u: +r\nwidget a: -crud\n"
+	if out != expect {
+		t.Errorf("expect %q, got %q", expect, out)
+	}
+
+	if !Escalates(Diff(a, b)) {
+		t.Error("expect escalation")
+	}
+
+	if Escalates(Diff(GetOwnerPerm(), GetAgentPerm())) {
+		t.Error("agent should not grant more than owner")
+	}
+
+	if len(Diff(nil, &common.Permission{})) != 0 {
+		t.Error("expect no change")
+	}
+}
+
+func TestLintPermString(t *testing.T) {
+	tcs := []struct {
+		perm     string
+		problems int
+	}{
+		{"u:-ru- a:crud s:----", 0},
+		{"", 0},
+		{"o:-r-- u:-ru-", 1},
+		{"o:---- u:-ru-", 0},
+		{"a:crux s:w", 2},
+		{"a", 1},
+	}
+
+	for _, tc := range tcs {
+		out := LintPermString(tc.perm)
+		if len(out) != tc.problems {
+			t.Errorf("[%s] expect %d problems, got %v", tc.perm, tc.problems, out)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	issues := LintRole("test", &common.Permission{
+		Agent:  ToPerm("a:-r-- s:crud"),
+		Widget: ToPerm("u:---d"),
+	}, &common.Permission{
+		Agent:  ToPerm("a:-r-- s:-r--"),
+		Widget: ToPerm("u:---d"),
+	})
+	if len(issues) != 2 {
+		t.Errorf("expect exceeding and suspicious issues, got %v", issues)
+	}
+
+	issues = LintScopes(map[string]string{
+		"test": "agent:r other_conversation:rw agents:r tag:x WhitelistIp:r",
+	})
+	if len(issues) != 3 {
+		t.Errorf("expect 3 issues, got %v", issues)
+	}
+}
+
+func TestResources(t *testing.T) {
+	numbers := make(map[int32]bool)
+	for _, r := range Resources {
+		if r.Number <= 0 || numbers[r.Number] {
+			t.Errorf("[%s] invalid or duplicated field number %d", r.Name, r.Number)
+		}
+		numbers[r.Number] = true
+
+		// the generated numbers must match the compiled protobuf
+		field := proto.MessageReflect(&common.Permission{}).Descriptor().Fields().ByName(protoreflect.Name(r.SnakeName))
+		if field == nil || int32(field.Number()) != r.Number {
+			t.Errorf("[%s] field number %d doesn't match the protobuf descriptor", r.Name, r.Number)
+		}
+
+		p := &common.Permission{}
+		r.Set(p, 0x123)
+		if r.Get(p) != 0x123 {
+			t.Errorf("[%s] expect 0x123, got %x", r.Name, r.Get(p))
+		}
+
+		base := MakeBase()
+		if r.Base() != r.Get(&base) {
+			t.Errorf("[%s] expect base %x, got %x", r.Name, r.Get(&base), r.Base())
+		}
+
+		found, err := FindResource(r.SnakeName)
+		if err != nil || found.Name != r.Name {
+			t.Errorf("[%s] expect found, got %v", r.SnakeName, err)
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	tcs := []struct {
+		perm string
+		v2   int64
+	}{
+		{"", 0x0200000000000000},
+		{"u:-ru- s:-r--", 0x0200000000040006},
+		{"u:crud a:crud s:crud", 0x02000000000F0F0F},
+		{"u:e a:-r--e", 0x0200000000001410},
+		{"g:cr-- a:r", 0x020000000C000400},
+	}
+
+	for _, tc := range tcs {
+		v1 := ToPerm(tc.perm)
+		v2 := ToPerm64(tc.perm)
+		if v2 != tc.v2 || Version(v2) != V2 || Version(int64(uint32(v1))) != V1 {
+			t.Errorf("[%s] expect %#x, got %#x", tc.perm, tc.v2, v2)
+		}
+
+		back, err := ToV1(v2)
+		if err != nil || back != v1 {
+			t.Errorf("[%s] expect %#x, got %#x, %v", tc.perm, v1, back, err)
+		}
+
+		if FormatPerm64(v2) != FormatPerm(v1) {
+			t.Errorf("[%s] expect %s, got %s", tc.perm, FormatPerm(v1), FormatPerm64(v2))
+		}
+
+		// checkPerm must give the same verdict for both encodings
+		for _, required := range []int32{CREATEPERM, READPERM, UPDATEPERM, DELETEPERM, EXPORTPERM} {
+			for _, ismine := range []bool{true, false} {
+				e1 := checkPerm(required, int64(uint32(v1)), ismine, !ismine, true)
+				e2 := checkPerm(required, v2, ismine, !ismine, true)
+				if (e1 == nil) != (e2 == nil) {
+					t.Errorf("[%s %x] expect %v, got %v", tc.perm, required, e1, e2)
+				}
+			}
+		}
+	}
+
+	if _, err := ToV1(ToPerm64("u:r") | 0xFF<<32); err == nil {
+		t.Error("expect error for levels V1 can't hold")
+	}
+
+	// bit 31, the last extra action of g:, makes V1 values negative
+	v1 := makePerm("g", 0x8C) | makePerm("u", 0x4)
+	wide := int64(uint32(v1))
+	if v1 >= 0 || Version(wide) != V1 || getPerm("g", wide) != 0x8C || getPerm("g", ToV2(v1)) != 0x8C {
+		t.Errorf("expect g:%#x in V1 %#x", 0x8C, wide)
+	}
+
+	for _, num := range []int64{wide, int64(v1), ToV2(v1)} {
+		if back, err := ToV1(num); err != nil || back != v1 {
+			t.Errorf("[%#x] expect %#x, got %#x, %v", num, v1, back, err)
+		}
+	}
+
+	// V2 permissions don't fit in common.Permission
+	cred := &common.Credential{AccountId: "ac1", Issuer: "ag1"}
+	perm := ToPerm64("g:cr-- a:-r--")
+	target := Target{AccountId: "ac1", Groups: []string{"gr1"}, CallerGroups: []string{"gr1"}}
+	if err := CheckPerm64("conversation", READPERM, perm, cred, Target{AccountId: "ac1"}); err != nil {
+		t.Error(err)
+	}
+
+	if err := CheckPerm64("Conversation", CREATEPERM, perm, cred, target); err != nil {
+		t.Error(err)
+	}
+
+	if err := CheckPerm64("conversation", CREATEPERM, perm, cred, Target{AccountId: "ac1"}); err == nil {
+		t.Error("expect err")
+	}
+
+	if err := CheckPerm64("unknown", READPERM, perm, cred, target); err == nil {
+		t.Error("expect unknown resource error")
+	}
+}
+
+func TestOwnershipResolver(t *testing.T) {
+	defer SetOwnershipResolver(nil)
+
+	cred := &common.Credential{
+		AccountId: "ac1",
+		Issuer:    "ag1",
+		Perm:      &common.Permission{Conversation: ToPerm("u:-ru-")},
+	}
+
+	// owned by a group, issuer alone isn't enough
+	if err := CheckReadConversation(cred, "ac1", "gr1"); err == nil {
+		t.Error("expect err")
+	}
+
+	groups := GroupOwnership{Groups: func(accid, agid string) []string {
+		if accid == "ac1" && agid == "ag1" {
+			return []string{"gr1"}
+		}
+		return nil
+	}}
+	delegated := DelegatedOwnership{Delegates: func(accid, owner string) []string {
+		if owner == "ag9" {
+			return []string{"ag1"}
+		}
+		return nil
+	}}
+	SetOwnershipResolver(AnyOwnership{IssuerOwnership{}, groups, delegated})
+
+	tcs := []struct {
+		desc   string
+		accid  string
+		owners []string
+		pass   bool
+	}{
+		{"issuer", "ac1", []string{"ag1"}, true},
+		{"group", "ac1", []string{"gr1"}, true},
+		{"delegated", "ac1", []string{"ag9"}, true},
+		{"other group", "ac1", []string{"gr2"}, false},
+		{"other account", "ac2", []string{"gr1"}, false},
+	}
+
+	for _, tc := range tcs {
+		err := CheckReadConversation(cred, tc.accid, tc.owners...)
+		if err == nil != tc.pass {
+			t.Errorf("[%s] expect pass: %v, but got err %v", tc.desc, tc.pass, err)
+		}
+	}
+
+	// assignees own conversations only
+	assigned := OwnershipFunc(func(cred *common.Credential, resource, accid string, owners []string) bool {
+		return contains("assignee:"+cred.GetIssuer(), owners)
+	})
+	SetOwnershipResolver(ResourceOwnership{Resolvers: map[string]OwnershipResolver{"Conversation": assigned}})
+	cred.Perm.CannedResponse = ToPerm("u:-ru-")
+	if err := CheckReadConversation(cred, "ac1", "assignee:ag1"); err != nil {
+		t.Errorf("expect assignee to own the conversation, got %v", err)
+	}
+
+	if err := CheckReadCannedResponse(cred, "ac1", "assignee:ag1"); err == nil {
+		t.Error("expect err, assignees don't own canned responses")
+	}
+
+	if err := CheckReadCannedResponse(cred, "ac1", "ag1"); err != nil {
+		t.Errorf("expect the default resolver for canned responses, got %v", err)
+	}
+
+	// resolvers without their function own nothing rather than panic
+	SetOwnershipResolver(AnyOwnership{GroupOwnership{}, DelegatedOwnership{}, nil})
+	if err := CheckReadConversation(cred, "ac1", "ag1"); err == nil {
+		t.Error("expect err")
+	}
+}
+
+func TestCondition(t *testing.T) {
+	attrs := map[string]interface{}{
+		"status": "open", "amount": 120, "vip": false, "invoice.total": int64(40),
+		"balance": -5, "i8": int8(-3), "i16": int16(300), "u": uint(7), "u8": uint8(200), "u16": uint16(9),
+	}
+	tcs := []struct {
+		expr   string
+		expect bool
+		err    bool
+	}{
+		{`status == "open"`, true, false},
+		{`status != 'open'`, false, false},
+		{`amount < 500 && invoice.total >= 40`, true, false},
+		{`vip || amount > 100`, true, false},
+		{`!(vip || amount > 200)`, true, false},
+		{`status < "p"`, true, false},
+		{`true || missing`, true, false},
+		{`missing == 1`, false, true},
+		{`amount == "120"`, false, true},
+		{`amount`, false, true},
+		{`balance < 0 && balance > -10`, true, false},
+		{`balance == -5`, true, false},
+		{`amount > -1.5`, true, false},
+		{`i8 == -3 && i16 > 299 && u == 7 && u8 >= 200 && u16 < 10`, true, false},
+	}
+
+	for _, tc := range tcs {
+		c, err := ParseCondition(tc.expr)
+		if err != nil {
+			t.Fatalf("[%s] %v", tc.expr, err)
+		}
+
+		ok, err := c.Eval(attrs)
+		if (err != nil) != tc.err || ok != tc.expect {
+			t.Errorf("[%s] expect %v (err %v), got %v, %v", tc.expr, tc.expect, tc.err, ok, err)
+		}
+	}
+
+	for _, expr := range []string{``, `status ==`, `(vip`, `vip)`, `"open`, `a = b`, `1.2.3 > 1`, `amount > -`, `amount - 1 > 0`} {
+		if _, err := ParseCondition(expr); err == nil {
+			t.Errorf("[%s] expect parse error", expr)
+		}
+	}
+
+	defer ResetConditions()
+	if err := AttachCondition("conversation", "update", "open"); err == nil {
+		t.Error("expect undefined condition error")
+	}
+
+	if err := RegisterCondition("open", `status == "open"`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AttachCondition("conversation", "update", "open"); err != nil {
+		t.Fatal(err)
+	}
+
+	cred := &common.Credential{AccountId: "ac1", Issuer: "ag1", Perm: &common.Permission{Conversation: ToPerm("u:cru-")}}
+	target := Target{AccountId: "ac1", Owners: []string{"ag1"}, Attributes: map[string]interface{}{"status": "open"}}
+	if err := CheckUpdateConversationTarget(cred, target); err != nil {
+		t.Errorf("expect pass, got %v", err)
+	}
+
+	// other actions are not guarded
+	if err := CheckReadConversation(cred, "ac1", "ag1"); err != nil {
+		t.Errorf("expect pass, got %v", err)
+	}
+
+	target.Attributes = map[string]interface{}{"status": "closed"}
+	if err := CheckUpdateConversationTarget(cred, target); err == nil {
+		t.Error("expect err for a closed conversation")
+	}
+
+	if e, err := ExplainTarget("conversation", "u", cred, target); err != nil || e.Allowed {
+		t.Errorf("expect deny, got %v, %v", e, err)
+	}
+
+	// without attributes the condition can't hold
+	if err := CheckUpdateConversation(cred, "ac1", "ag1"); err == nil {
+		t.Error("expect err without attributes")
+	}
+}
+
+func TestGrant(t *testing.T) {
+	now := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+
+	role := &common.Permission{Subscription: ToPerm("a:-r--")}
+	temp := GrantFor(&common.Permission{Subscription: ToPerm("a:-ru-")}, 2*time.Hour)
+	later := Grant{Perm: &common.Permission{Invoice: ToPerm("a:-r--")}, NotBefore: now.Add(time.Hour)}
+
+	tcs := []struct {
+		desc         string
+		at           time.Time
+		subscription int32
+		invoice      int32
+	}{
+		{"start", now, ToPerm("a:-ru-"), 0},
+		{"after an hour", now.Add(time.Hour), ToPerm("a:-ru-"), ToPerm("a:-r--")},
+		{"last instant", now.Add(2 * time.Hour), ToPerm("a:-ru-"), ToPerm("a:-r--")},
+		{"expired", now.Add(2*time.Hour + time.Second), ToPerm("a:-r--"), ToPerm("a:-r--")},
+		{"before", now.Add(-time.Second), ToPerm("a:-r--"), 0},
+	}
+
+	for _, tc := range tcs {
+		now = tc.at
+		p := MergeGrants(role, temp, later)
+		if p.GetSubscription() != tc.subscription || p.GetInvoice() != tc.invoice {
+			t.Errorf("[%s] expect %s, %s, got %s, %s", tc.desc, FormatPerm(tc.subscription), FormatPerm(tc.invoice),
+				FormatPerm(p.GetSubscription()), FormatPerm(p.GetInvoice()))
+		}
+	}
+
+	if p := Resolve(nil, now); p.GetSubscription() != 0 {
+		t.Errorf("expect empty permission, got %v", p)
+	}
+}
+
+func TestImpersonation(t *testing.T) {
+	support := &common.Credential{AccountId: "subiz", Issuer: "sp1", Perm: &common.Permission{Conversation: ToPerm("s:-ru-")}}
+	agent := &common.Credential{AccountId: "ac1", Issuer: "ag1", Perm: &common.Permission{Conversation: ToPerm("u:cru- a:-r--")}}
+	target := Target{AccountId: "ac1", Owners: []string{"ag1"}}
+
+	tcs := []struct {
+		desc   string
+		actor  *common.Credential
+		action string
+		target Target
+		pass   bool
+	}{
+		{"both allow", support, "update", target, true},
+		{"target denies", support, "update", Target{AccountId: "ac1", Owners: []string{"ag2"}}, false},
+		{"actor denies", support, "create", target, false},
+		{"actor not super", agent, "read", target, false},
+		{"unknown action", support, "export", target, false},
+	}
+
+	for _, tc := range tcs {
+		d := Impersonation{Actor: tc.actor, Target: agent}.Check("conversation", tc.action, tc.target)
+		if d.Allowed != tc.pass || (d.Err == nil) != tc.pass {
+			t.Errorf("[%s] expect pass: %v, got %s", tc.desc, tc.pass, d)
+		}
+
+		if d.ActorIssuer != tc.actor.Issuer || d.TargetIssuer != "ag1" || d.TargetAccountId != "ac1" {
+			t.Errorf("[%s] expect both identities recorded, got %s", tc.desc, d)
+		}
+	}
+
+	// service account actors follow the principal rules
+	now := time.Now()
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+	defer RemoveServiceAccount("support_bot")
+	defer RemoveServiceAccount("pinned_bot")
+	err := RegisterServiceAccount(ServiceAccount{
+		Id:       "support_bot",
+		Type:     ServiceAccountPrincipal,
+		ExpireAt: now.Add(time.Hour),
+		Perm:     support.Perm,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = RegisterServiceAccount(ServiceAccount{Id: "pinned_bot", Type: APIKeyPrincipal, AccountId: "ac2", Perm: support.Perm})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot := lookupServiceAccount("support_bot").Credential("subiz")
+	if d := (Impersonation{Actor: bot, Target: agent}).Check("conversation", "update", target); !d.Allowed {
+		t.Errorf("expect pass before expiry, got %s", d)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if d := (Impersonation{Actor: bot, Target: agent}).Check("conversation", "update", target); d.Allowed {
+		t.Errorf("expect expired actor denied, got %s", d)
+	}
+
+	pinned := lookupServiceAccount("pinned_bot").Credential("subiz")
+	if d := (Impersonation{Actor: pinned, Target: agent}).Check("conversation", "update", target); d.Allowed {
+		t.Errorf("expect actor pinned to another account denied, got %s", d)
+	}
+}
+
+func TestDownscope(t *testing.T) {
+	cred := &common.Credential{AccountId: "ac1", Issuer: "ag1", Perm: GetOwnerPerm()}
+	token := Downscope(cred, []string{"view_other_convos"})
+	if token == nil {
+		t.Fatal("expect token")
+	}
+
+	if token.AccountId != "ac1" || token.Issuer != "ag1" {
+		t.Errorf("expect identity kept, got %v", token)
+	}
+
+	if err := CheckReadConversation(token, "ac1", "ag2"); err != nil {
+		t.Errorf("expect read other's conversation, got %v", err)
+	}
+
+	// the owner may update conversations, the scope doesn't allow it
+	if err := CheckUpdateConversation(token, "ac1", "ag1"); err == nil {
+		t.Error("expect err updating conversation")
+	}
+
+	if err := CheckUpdateSubscription(token, "ac1"); err == nil {
+		t.Error("expect err updating subscription")
+	}
+
+	// never exceeds the issuer
+	p := IntersectPermission(token.Perm, cred.Perm)
+	if !proto.Equal(p, token.Perm) {
+		t.Errorf("expect token within issuer, got %s", FormatPermission(token.Perm))
+	}
+
+	agent := &common.Credential{AccountId: "ac1", Issuer: "ag2", Perm: GetAgentPerm()}
+	tcs := []struct {
+		desc   string
+		cred   *common.Credential
+		scopes []string
+		pass   bool
+	}{
+		{"held", agent, []string{"view_other_convos"}, true},
+		{"not held", agent, []string{"account_manage"}, false},
+		{"invoice:r not held", agent, []string{"agent"}, false},
+		{"export not held", agent, []string{"export_user"}, false},
+		{"unknown", cred, []string{"agents"}, false},
+		{"nil", nil, []string{"agent"}, false},
+	}
+
+	for _, tc := range tcs {
+		if token := Downscope(tc.cred, tc.scopes); (token != nil) != tc.pass {
+			t.Errorf("[%s] expect pass: %v, got %v", tc.desc, tc.pass, token)
+		}
+	}
+
+	// Base never allows deleting conversations, conversation:w needs create
+	// and update at a single level
+	defer SetPolicy(CurrentPolicy())
+	if err := RegisterScope("test_write", ScopeDef{Entries: "conversation:w"}); err != nil {
+		t.Fatal(err)
+	}
+
+	partials := []struct {
+		perm   string
+		expect string // the token's permission, empty when refused
+	}{
+		{"u:cru-", "u:c-u- a:---- s:----"},
+		{"g:--u-", "u:---- g:--u- a:---- s:----"},
+		{"u:cr-d", ""},
+		{"u:---d", ""},
+		{"u:c--- g:-r--", ""},
+		{"a:crud", ""},
+		{"s:crud", ""},
+	}
+	for _, tc := range partials {
+		cred := &common.Credential{AccountId: "ac1", Issuer: "ag1", Perm: &common.Permission{Conversation: ToPerm(tc.perm)}}
+		token := Downscope(cred, []string{"test_write"})
+		if tc.expect == "" {
+			if token != nil {
+				t.Errorf("[%s] expect refused, got %s", tc.perm, FormatPerm(token.Perm.Conversation))
+			}
+			continue
+		}
+
+		if token == nil || FormatPerm(token.Perm.Conversation) != tc.expect {
+			t.Errorf("[%s] expect %s, got %v", tc.perm, tc.expect, token)
+		}
+	}
+}
+
+func TestServiceAccount(t *testing.T) {
+	now := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+	defer RemoveServiceAccount("webhook1")
+	defer RemoveServiceAccount("key1")
+
+	perm := &common.Permission{Conversation: ToPerm("u:crud a:-r--")}
+	if err := RegisterServiceAccount(ServiceAccount{Id: "webhook1", Type: ServiceAccountPrincipal, Perm: perm}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := RegisterServiceAccount(ServiceAccount{
+		Id:        "key1",
+		Type:      APIKeyPrincipal,
+		AccountId: "ac1",
+		ExpireAt:  now.Add(time.Hour),
+		Perm:      perm,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterServiceAccount(ServiceAccount{Id: "x", Perm: perm}); err == nil {
+		t.Error("expect invalid type error")
+	}
+
+	webhook := lookupServiceAccount("webhook1").Credential("ac1")
+	key := lookupServiceAccount("key1").Credential("ac2")
+	if PrincipalOf(webhook) != ServiceAccountPrincipal || PrincipalOf(key) != APIKeyPrincipal ||
+		PrincipalOf(&common.Credential{Issuer: "ag1"}) != AgentPrincipal {
+		t.Error("wrong principal type")
+	}
+
+	// u: grants never apply, even when the principal is listed as owner
+	if err := CheckUpdateConversation(webhook, "ac1", "webhook1"); err == nil {
+		t.Error("expect err, service accounts own nothing")
+	}
+
+	if err := CheckReadConversation(webhook, "ac1", "webhook1"); err != nil {
+		t.Errorf("expect pass at account level, got %v", err)
+	}
+
+	// pinned to ac1, the credential's account doesn't matter
+	if err := CheckReadConversation(key, "ac2"); err == nil {
+		t.Error("expect err outside of the pinned account")
+	}
+
+	// nor do s: grants
+	defer RemoveServiceAccount("key2")
+	super := &common.Permission{Conversation: ToPerm("s:-r--")}
+	if err := RegisterServiceAccount(ServiceAccount{Id: "key2", Type: APIKeyPrincipal, AccountId: "ac1", Perm: super}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckReadConversation(lookupServiceAccount("key2").Credential("ac1"), "ac2"); err == nil {
+		t.Error("expect err, s: doesn't escape the pinned account")
+	}
+
+	if err := CheckReadConversation(lookupServiceAccount("key2").Credential("ac1"), "ac1"); err != nil {
+		t.Errorf("expect pass in the pinned account, got %v", err)
+	}
+
+	key.AccountId = "ac1"
+	if err := CheckReadConversation(key, "ac1"); err != nil {
+		t.Errorf("expect pass in the pinned account, got %v", err)
+	}
+
+	// the credential never has more than the principal's permission
+	key.Perm = &common.Permission{Conversation: ToPerm("a:crud")}
+	if err := CheckUpdateConversation(key, "ac1"); err == nil {
+		t.Error("expect err, beyond the permission of the principal")
+	}
+
+	// the registry keeps its own copy
+	perm.Conversation = ToPerm("a:crud")
+	if err := CheckUpdateConversation(key, "ac1"); err == nil {
+		t.Error("expect err, the registered permission is a copy")
+	}
+
+	// re-registering applies to credentials already issued
+	err = RegisterServiceAccount(ServiceAccount{
+		Id:        "key1",
+		Type:      APIKeyPrincipal,
+		AccountId: "ac1",
+		ExpireAt:  now.Add(time.Hour),
+		Perm:      &common.Permission{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckReadConversation(key, "ac1"); err == nil {
+		t.Error("expect err, the principal lost its permission")
+	}
+
+	if e, err := ExplainCheck("conversation", "r", key, "ac1"); err != nil || e.Allowed || e.CallerPerm != 0 {
+		t.Errorf("expect deny, got %v, %v", e, err)
+	}
+
+	if err := CheckPerm64("conversation", READPERM, ToPerm64("a:-r--"), key, Target{AccountId: "ac1"}); err == nil {
+		t.Error("expect err, the V2 permission is bounded too")
+	}
+
+	err = RegisterServiceAccount(ServiceAccount{
+		Id:        "key1",
+		Type:      APIKeyPrincipal,
+		AccountId: "ac1",
+		ExpireAt:  now.Add(time.Hour),
+		Perm:      &common.Permission{Conversation: ToPerm("a:-r--")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := CheckReadConversation(key, "ac1"); err != nil {
+		t.Errorf("expect pass before expiry, got %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := CheckReadConversation(key, "ac1"); err == nil {
+		t.Error("expect err after expiry")
+	}
+
+	if e, err := ExplainCheck("conversation", "r", key, "ac1"); err != nil || e.Allowed {
+		t.Errorf("expect deny, got %v, %v", e, err)
+	}
+}
+
+func TestDescribeScope(t *testing.T) {
+	info, err := DescribeScope("export_user", "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Title != "Export users" || info.Sensitivity != "high" || strings.Join(info.Resources, " ") != "user:e" {
+		t.Errorf("unexpected %+v", info)
+	}
+
+	// the resource list is resolved through the parents
+	info, _ = DescribeScope("account_setting", "en")
+	if strings.Join(info.Resources, " ") != CurrentPolicy().Scopes()["account_setting"].String() {
+		t.Errorf("expect %s, got %v", CurrentPolicy().Scopes()["account_setting"], info.Resources)
+	}
+
+	SetTranslator(func(lang, key, fallback string) string {
+		if lang == "vi" && key == "scope.export_user.title" {
+			return "Xuất danh sách người dùng"
+		}
+		return fallback
+	})
+	defer SetTranslator(nil)
+
+	if info, _ := DescribeScope("export_user", "vi"); info.Title != "Xuất danh sách người dùng" || info.Description == "" {
+		t.Errorf("unexpected %+v", info)
+	}
+
+	if _, err := DescribeScope("nope", "en"); err == nil {
+		t.Error("expect undefined scope error")
+	}
+
+	if len(DescribeScopes("en")) != len(CurrentPolicy().Scopes()) {
+		t.Error("expect every scope described")
+	}
+
+	issues := LintScopeDefs(map[string]ScopeDef{
+		"a": {Title: "A", Description: "a", Sensitivity: SensitivityHigh},
+		"b": {Parents: []string{"a"}, Sensitivity: SensitivityLow},
+		"c": {Title: "C", Description: "a", Sensitivity: SensitivityHigh},
+	})
+	if len(issues) != 4 || issues[3].String() != "scope c: same description as scope a" {
+		t.Errorf("expect 4 issues, got %v", issues)
+	}
+
+	owner, _ := DescribeScope("owner", "en")
+	all, _ := DescribeScope("all", "en")
+	if owner.Description == all.Description {
+		t.Error("expect owner and all described differently")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	defer SetPolicy(nil)
+	// neither the compatibility views nor the copies the accessors return
+	// can change the policy
+	Base.Agent = 0
+	Scopes["agent"] = ScopeSet{}
+	defer func() {
+		Base.Agent = defaultPolicy.base.Agent
+		Scopes["agent"] = defaultPolicy.scopes["agent"]
+	}()
+
+	base := MakeBase()
+	base.Agent = 0
+	CurrentPolicy().Scopes()["agent"] = ScopeSet{}
+	if role, _ := Role("agent"); role != nil {
+		role.Conversation = 0
+	}
+
+	if MakeBase().Agent == 0 || !Access([]string{"agent"}, "conversation:rw") {
+		t.Error("expect the policy unchanged")
+	}
+
+	if role, ok := Role("agent"); !ok || role.Conversation == 0 {
+		t.Error("expect the role unchanged")
+	}
+
+	if err := RegisterScope("test_a", ScopeDef{Parents: []string{"test_b"}}); err == nil {
+		t.Error("expect undefined parent error")
+	}
+
+	if err := RegisterScope("test_webhook", ScopeDef{Parents: []string{"view_other_convos"}, Entries: "tag:r"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !Access([]string{"test_webhook"}, "tag:r other_conversation:r") {
+		t.Error("expect registered scope used")
+	}
+
+	before := CurrentPolicy()
+	if err := SetBase(&common.Permission{Agent: ToPerm("a:-r--")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if MakeBase().Agent != ToPerm("a:-r--") || before.Base().Agent == ToPerm("a:-r--") {
+		t.Error("expect a new snapshot")
+	}
+
+	if !strings.HasPrefix(CurrentPolicy().Scopes()["test_webhook"].String(), "other_conversation:r") {
+		t.Error("expect Scopes to read the current policy")
+	}
+
+	if r, _ := FindResource("agent"); r.Base() != ToPerm("a:-r--") {
+		t.Errorf("expect Resource.Base to read the current policy, got %x", r.Base())
+	}
+
+	if _, ok := Scopes["test_webhook"]; ok || Base.Agent == ToPerm("a:-r--") {
+		t.Error("expect the compatibility views unchanged")
+	}
+
+	// a snapshot never changes
+	set, _ := before.Scope("test_webhook")
+	set["rule"] = 0xF
+	if s, _ := before.Scope("test_webhook"); s.String() != "other_conversation:r tag:r" {
+		t.Errorf("expect snapshot unchanged, got %s", s)
+	}
+}
+
+func TestPolicyManyScopes(t *testing.T) {
+	defs := map[string]ScopeDef{}
+	for i := 0; i < maxCompiledScopes+1; i++ {
+		defs[fmt.Sprintf("s%d", i)] = ScopeDef{Entries: fmt.Sprintf("r%d:r", i)}
+	}
+
+	p, err := NewPolicy(nil, defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.compiled != nil || !p.access([]string{"s3", "s64"}, "r3:r r64:r") || p.access([]string{"s3"}, "r4:r") {
+		t.Error("expect evaluation without compiled scopes")
+	}
+
+	// only the compiled form is allocation free
+	allocs := testing.AllocsPerRun(100, func() { p.access([]string{"s3"}, "r3:r") })
+	if allocs == 0 {
+		t.Error("expect the uncompiled policy to allocate")
+	}
+
+	allocs = testing.AllocsPerRun(100, func() { Access([]string{"agent", "view_other_convos"}, "conversation:rw other_conversation:r") })
+	if allocs != 0 {
+		t.Errorf("expect compiled Access not to allocate, got %v allocs", allocs)
+	}
+}
+
+// BenchmarkAccessManyScopes measures Access on a policy with more scopes
+// than can be compiled
+func BenchmarkAccessManyScopes(b *testing.B) {
+	defs := map[string]ScopeDef{}
+	for i := 0; i < maxCompiledScopes+1; i++ {
+		defs[fmt.Sprintf("s%d", i)] = ScopeDef{Entries: fmt.Sprintf("r%d:r", i)}
+	}
+
+	p, err := NewPolicy(nil, defs)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	scopes := []string{"s3", "s64"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.access(scopes, "r3:r r64:r")
+	}
+}
+
+// TestPolicyRace is meant to run with -race
+func TestPolicyRace(t *testing.T) {
+	defer SetPolicy(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				name := fmt.Sprintf("test_%d_%d", i, j)
+				if err := RegisterScope(name, ScopeDef{Parents: []string{"agent"}, Entries: "rule:w"}); err != nil {
+					t.Error(err)
+					return
+				}
+				SetBase(CurrentPolicy().Base())
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			cred := &common.Credential{AccountId: "ac1", Issuer: "ag1", Perm: GetOwnerPerm()}
+			for j := 0; j < 50; j++ {
+				Access([]string{"agent"}, "conversation:rw")
+				AccessCheck([]string{"account_manage"}, "payment_method:w")
+				Downscope(cred, []string{"agent"})
+				DescribeScopes("en")
+				ScopesIncluding("agent")
+				Lint()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(CurrentPolicy().ScopeNames()) != len(defaultPolicy.defs)+4*20 {
+		t.Errorf("expect every registration kept, got %d scopes", len(CurrentPolicy().ScopeNames()))
+	}
+}
+
+func TestAsPrincipal(t *testing.T) {
+	cred := &common.Credential{
+		AccountId: "ac1",
+		Issuer:    "ag1",
+		Perm:      &common.Permission{Conversation: ToPerm("u:-r-- a:-r--"), AgentGroup: ToPerm("a:crud")},
+	}
+	p := AsPrincipal(cred)
+	if p.AccountId() != "ac1" || p.Issuer() != "ag1" {
+		t.Fatalf("got %s %s", p.AccountId(), p.Issuer())
+	}
+
+	if p.Perm("conversation") != int64(cred.Perm.Conversation) || p.Perm("agent_group") != int64(cred.Perm.AgentGroup) ||
+		p.Perm("AgentGroup") != int64(cred.Perm.AgentGroup) || p.Perm("unknown") != 0 {
+		t.Error("wrong permission lookup")
+	}
+
+	tcs := []struct {
+		resource string
+		required int32
+		target   core.Target
+		allowed  bool
+	}{
+		{"conversation", core.Read, core.Target{AccountId: "ac1"}, true},
+		{"conversation", core.Update, core.Target{AccountId: "ac1", Owners: []string{"ag1"}}, false},
+		{"conversation", core.Read, core.Target{AccountId: "ac2"}, false},
+		{"agent_group", core.Create | core.Delete, core.Target{AccountId: "ac1"}, true},
+		{"user", core.Read, core.Target{AccountId: "ac1"}, false},
+	}
+	for i, tc := range tcs {
+		err := core.Check(p, tc.resource, tc.required, tc.target)
+		if (err == nil) != tc.allowed {
+			t.Errorf("case %d: got %v, expect allowed %v", i, err, tc.allowed)
+		}
+
+		// the adapter and the common based API agree
+		old := checkTarget(tc.resource, tc.required, p.Perm(tc.resource), cred, tc.target)
+		if (old == nil) != (err == nil) {
+			t.Errorf("case %d: core %v, perm %v", i, err, old)
+		}
+	}
+
+	// the ownership resolver applies
+	SetOwnershipResolver(OwnershipFunc(func(*common.Credential, string, string, []string) bool { return true }))
+	defer SetOwnershipResolver(nil)
+	cred.Perm.Conversation = ToPerm("u:-ru-")
+	if err := core.Check(p, "conversation", core.Update, core.Target{AccountId: "ac1", Owners: []string{"ag2"}}); err != nil {
+		t.Errorf("expect resolver to make ag1 owner, got %v", err)
+	}
+
+	// so do the conditions
+	defer ResetConditions()
+	if err := RegisterCondition("open", `status == "open"`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AttachCondition("conversation", "u", "open"); err != nil {
+		t.Fatal(err)
+	}
+
+	closed := core.Target{AccountId: "ac1", Attributes: map[string]interface{}{"status": "closed"}}
+	if err := core.Check(p, "conversation", core.Update, closed); err == nil {
+		t.Error("expect err, condition not met")
+	}
+
+	if err := CheckUpdateConversationTarget(cred, closed); err == nil {
+		t.Error("expect err, condition not met")
+	}
+
+	// and the service account rules: no u:, pinning bounding s:, expiry
+	now := time.Now()
+	SetClock(func() time.Time { return now })
+	defer SetClock(nil)
+	defer RemoveServiceAccount("key1")
+	err := RegisterServiceAccount(ServiceAccount{
+		Id:        "key1",
+		Type:      APIKeyPrincipal,
+		AccountId: "ac1",
+		ExpireAt:  now.Add(time.Hour),
+		Perm:      &common.Permission{Conversation: ToPerm("u:crud s:-r--")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := lookupServiceAccount("key1").Credential("ac1")
+	sa := []struct {
+		required int32
+		target   core.Target
+		allowed  bool
+	}{
+		{core.Read, core.Target{AccountId: "ac1"}, true},
+		{core.Update, core.Target{AccountId: "ac1", Owners: []string{"key1"}}, false},
+		{core.Read, core.Target{AccountId: "ac2"}, false},
+	}
+	for i, tc := range sa {
+		err := core.Check(AsPrincipal(key), "conversation", tc.required, tc.target)
+		if (err == nil) != tc.allowed {
+			t.Errorf("service account case %d: got %v, expect allowed %v", i, err, tc.allowed)
+		}
+
+		old := checkTarget("Conversation", tc.required, int64(uint32(key.Perm.Conversation)), key, tc.target)
+		if (old == nil) != (err == nil) {
+			t.Errorf("service account case %d: core %v, perm %v", i, err, old)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if err := core.Check(AsPrincipal(key), "conversation", core.Read, core.Target{AccountId: "ac1"}); err == nil {
+		t.Error("expect err after expiry")
+	}
+}
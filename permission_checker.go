@@ -1,4 +1,4 @@
-// Code generated by "perm_generator -file vendor/github.com/subiz/header/common/common.pb.go -type Permission"; DO NOT EDIT.
+// Code generated by "perm_generator -pkg github.com/subiz/header/common -type Permission -actions actions.txt"; DO NOT EDIT.
 
 package perm
 
@@ -13,1036 +13,1552 @@ func contains(s string, ss []string) bool {
 	return false
 }
 
-var CREATEPERM = strPermToInt("c")
-var READPERM = strPermToInt("r")
-var UPDATEPERM = strPermToInt("u")
-var DELETEPERM = strPermToInt("d")
+// the permission bits of the actions, see strPermToInt
+const (
+	CREATEPERM int32 = 0x8
+	READPERM   int32 = 0x4
+	UPDATEPERM int32 = 0x2
+	DELETEPERM int32 = 0x1
+)
 
 func CheckCreateAccount(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAccount()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Account", CREATEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAccountTarget is CheckCreateAccount for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAccountTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Account", CREATEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, t)
 }
 
 func CheckReadAccount(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAccount()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Account", READPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAccountTarget is CheckReadAccount for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAccountTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Account", READPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, t)
 }
 
 func CheckUpdateAccount(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAccount()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Account", UPDATEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAccountTarget is CheckUpdateAccount for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAccountTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Account", UPDATEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, t)
 }
 
 func CheckDeleteAccount(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAccount()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Account", DELETEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteAccountTarget is CheckDeleteAccount for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAccountTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Account", DELETEPERM, int64(uint32(cred.GetPerm().GetAccount())), cred, t)
+}
+
 func CheckCreateAgent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Agent", CREATEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentTarget is CheckCreateAgent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Agent", CREATEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, t)
 }
 
 func CheckReadAgent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Agent", READPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentTarget is CheckReadAgent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Agent", READPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, t)
 }
 
 func CheckUpdateAgent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Agent", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentTarget is CheckUpdateAgent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Agent", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, t)
 }
 
 func CheckDeleteAgent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Agent", DELETEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteAgentTarget is CheckDeleteAgent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Agent", DELETEPERM, int64(uint32(cred.GetPerm().GetAgent())), cred, t)
 }
+
 func CheckCreateAgentPassword(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPassword()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPassword", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentPasswordTarget is CheckCreateAgentPassword for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentPasswordTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPassword", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, t)
 }
 
 func CheckReadAgentPassword(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPassword()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPassword", READPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentPasswordTarget is CheckReadAgentPassword for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentPasswordTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPassword", READPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, t)
 }
 
 func CheckUpdateAgentPassword(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPassword()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPassword", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentPasswordTarget is CheckUpdateAgentPassword for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentPasswordTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPassword", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, t)
 }
 
 func CheckDeleteAgentPassword(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPassword()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPassword", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteAgentPasswordTarget is CheckDeleteAgentPassword for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentPasswordTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPassword", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPassword())), cred, t)
 }
+
 func CheckCreatePermission(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPermission()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Permission", CREATEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePermissionTarget is CheckCreatePermission for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePermissionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Permission", CREATEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, t)
 }
 
 func CheckReadPermission(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPermission()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Permission", READPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPermissionTarget is CheckReadPermission for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPermissionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Permission", READPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, t)
 }
 
 func CheckUpdatePermission(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPermission()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Permission", UPDATEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePermissionTarget is CheckUpdatePermission for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePermissionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Permission", UPDATEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, t)
 }
 
 func CheckDeletePermission(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPermission()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Permission", DELETEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeletePermissionTarget is CheckDeletePermission for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePermissionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Permission", DELETEPERM, int64(uint32(cred.GetPerm().GetPermission())), cred, t)
+}
+
 func CheckCreateAgentGroup(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentGroup()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentGroup", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentGroupTarget is CheckCreateAgentGroup for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentGroupTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentGroup", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, t)
 }
 
 func CheckReadAgentGroup(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentGroup()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentGroup", READPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentGroupTarget is CheckReadAgentGroup for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentGroupTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentGroup", READPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, t)
 }
 
 func CheckUpdateAgentGroup(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentGroup()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentGroup", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentGroupTarget is CheckUpdateAgentGroup for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentGroupTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentGroup", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, t)
 }
 
 func CheckDeleteAgentGroup(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentGroup()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentGroup", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteAgentGroupTarget is CheckDeleteAgentGroup for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentGroupTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentGroup", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentGroup())), cred, t)
 }
+
 func CheckCreateSegmentation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSegmentation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Segmentation", CREATEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateSegmentationTarget is CheckCreateSegmentation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateSegmentationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Segmentation", CREATEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, t)
 }
 
 func CheckReadSegmentation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSegmentation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Segmentation", READPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadSegmentationTarget is CheckReadSegmentation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadSegmentationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Segmentation", READPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, t)
 }
 
 func CheckUpdateSegmentation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSegmentation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Segmentation", UPDATEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateSegmentationTarget is CheckUpdateSegmentation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateSegmentationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Segmentation", UPDATEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, t)
 }
 
 func CheckDeleteSegmentation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSegmentation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Segmentation", DELETEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteSegmentationTarget is CheckDeleteSegmentation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteSegmentationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Segmentation", DELETEPERM, int64(uint32(cred.GetPerm().GetSegmentation())), cred, t)
 }
+
 func CheckCreateClient(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetClient()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Client", CREATEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateClientTarget is CheckCreateClient for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateClientTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Client", CREATEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, t)
 }
 
 func CheckReadClient(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetClient()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Client", READPERM, int64(uint32(cred.GetPerm().GetClient())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadClientTarget is CheckReadClient for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadClientTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Client", READPERM, int64(uint32(cred.GetPerm().GetClient())), cred, t)
 }
 
 func CheckUpdateClient(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetClient()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Client", UPDATEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateClientTarget is CheckUpdateClient for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateClientTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Client", UPDATEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, t)
 }
 
 func CheckDeleteClient(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetClient()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Client", DELETEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteClientTarget is CheckDeleteClient for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteClientTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Client", DELETEPERM, int64(uint32(cred.GetPerm().GetClient())), cred, t)
+}
+
 func CheckCreateRule(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetRule()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Rule", CREATEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateRuleTarget is CheckCreateRule for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateRuleTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Rule", CREATEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, t)
 }
 
 func CheckReadRule(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetRule()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Rule", READPERM, int64(uint32(cred.GetPerm().GetRule())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadRuleTarget is CheckReadRule for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadRuleTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Rule", READPERM, int64(uint32(cred.GetPerm().GetRule())), cred, t)
 }
 
 func CheckUpdateRule(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetRule()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Rule", UPDATEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateRuleTarget is CheckUpdateRule for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateRuleTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Rule", UPDATEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, t)
 }
 
 func CheckDeleteRule(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetRule()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Rule", DELETEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteRuleTarget is CheckDeleteRule for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteRuleTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Rule", DELETEPERM, int64(uint32(cred.GetPerm().GetRule())), cred, t)
+}
+
 func CheckCreateConversation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Conversation", CREATEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateConversationTarget is CheckCreateConversation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateConversationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Conversation", CREATEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, t)
 }
 
 func CheckReadConversation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Conversation", READPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadConversationTarget is CheckReadConversation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadConversationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Conversation", READPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, t)
 }
 
 func CheckUpdateConversation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Conversation", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateConversationTarget is CheckUpdateConversation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateConversationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Conversation", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, t)
 }
 
 func CheckDeleteConversation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Conversation", DELETEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteConversationTarget is CheckDeleteConversation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteConversationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Conversation", DELETEPERM, int64(uint32(cred.GetPerm().GetConversation())), cred, t)
 }
+
 func CheckCreateIntegration(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetIntegration()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Integration", CREATEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateIntegrationTarget is CheckCreateIntegration for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateIntegrationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Integration", CREATEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, t)
 }
 
 func CheckReadIntegration(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetIntegration()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Integration", READPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadIntegrationTarget is CheckReadIntegration for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadIntegrationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Integration", READPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, t)
 }
 
 func CheckUpdateIntegration(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetIntegration()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Integration", UPDATEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateIntegrationTarget is CheckUpdateIntegration for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateIntegrationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Integration", UPDATEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, t)
 }
 
 func CheckDeleteIntegration(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetIntegration()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Integration", DELETEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteIntegrationTarget is CheckDeleteIntegration for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteIntegrationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Integration", DELETEPERM, int64(uint32(cred.GetPerm().GetIntegration())), cred, t)
+}
+
 func CheckCreateCannedResponse(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCannedResponse()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("CannedResponse", CREATEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateCannedResponseTarget is CheckCreateCannedResponse for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateCannedResponseTarget(cred *common.Credential, t Target) error {
+	return checkTarget("CannedResponse", CREATEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, t)
 }
 
 func CheckReadCannedResponse(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCannedResponse()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("CannedResponse", READPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadCannedResponseTarget is CheckReadCannedResponse for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadCannedResponseTarget(cred *common.Credential, t Target) error {
+	return checkTarget("CannedResponse", READPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, t)
 }
 
 func CheckUpdateCannedResponse(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCannedResponse()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("CannedResponse", UPDATEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateCannedResponseTarget is CheckUpdateCannedResponse for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateCannedResponseTarget(cred *common.Credential, t Target) error {
+	return checkTarget("CannedResponse", UPDATEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, t)
 }
 
 func CheckDeleteCannedResponse(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCannedResponse()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("CannedResponse", DELETEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteCannedResponseTarget is CheckDeleteCannedResponse for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteCannedResponseTarget(cred *common.Credential, t Target) error {
+	return checkTarget("CannedResponse", DELETEPERM, int64(uint32(cred.GetPerm().GetCannedResponse())), cred, t)
+}
+
 func CheckCreateTag(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetTag()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Tag", CREATEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateTagTarget is CheckCreateTag for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateTagTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Tag", CREATEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, t)
 }
 
 func CheckReadTag(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetTag()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Tag", READPERM, int64(uint32(cred.GetPerm().GetTag())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadTagTarget is CheckReadTag for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadTagTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Tag", READPERM, int64(uint32(cred.GetPerm().GetTag())), cred, t)
 }
 
 func CheckUpdateTag(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetTag()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Tag", UPDATEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateTagTarget is CheckUpdateTag for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateTagTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Tag", UPDATEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, t)
 }
 
 func CheckDeleteTag(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetTag()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Tag", DELETEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteTagTarget is CheckDeleteTag for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteTagTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Tag", DELETEPERM, int64(uint32(cred.GetPerm().GetTag())), cred, t)
+}
+
 func CheckCreateWhitelistIp(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistIp()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistIp", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateWhitelistIpTarget is CheckCreateWhitelistIp for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateWhitelistIpTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistIp", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, t)
 }
 
 func CheckReadWhitelistIp(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistIp()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistIp", READPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadWhitelistIpTarget is CheckReadWhitelistIp for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadWhitelistIpTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistIp", READPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, t)
 }
 
 func CheckUpdateWhitelistIp(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistIp()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistIp", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateWhitelistIpTarget is CheckUpdateWhitelistIp for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateWhitelistIpTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistIp", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, t)
 }
 
 func CheckDeleteWhitelistIp(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistIp()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistIp", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteWhitelistIpTarget is CheckDeleteWhitelistIp for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteWhitelistIpTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistIp", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistIp())), cred, t)
 }
+
 func CheckCreateWhitelistUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistUser", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateWhitelistUserTarget is CheckCreateWhitelistUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateWhitelistUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistUser", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, t)
 }
 
 func CheckReadWhitelistUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistUser", READPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadWhitelistUserTarget is CheckReadWhitelistUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadWhitelistUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistUser", READPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, t)
 }
 
 func CheckUpdateWhitelistUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistUser", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateWhitelistUserTarget is CheckUpdateWhitelistUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateWhitelistUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistUser", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, t)
 }
 
 func CheckDeleteWhitelistUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistUser", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteWhitelistUserTarget is CheckDeleteWhitelistUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteWhitelistUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistUser", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistUser())), cred, t)
 }
+
 func CheckCreateWhitelistDomain(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistDomain()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistDomain", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateWhitelistDomainTarget is CheckCreateWhitelistDomain for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateWhitelistDomainTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistDomain", CREATEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, t)
 }
 
 func CheckReadWhitelistDomain(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistDomain()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistDomain", READPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadWhitelistDomainTarget is CheckReadWhitelistDomain for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadWhitelistDomainTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistDomain", READPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, t)
 }
 
 func CheckUpdateWhitelistDomain(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistDomain()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistDomain", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateWhitelistDomainTarget is CheckUpdateWhitelistDomain for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateWhitelistDomainTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistDomain", UPDATEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, t)
 }
 
 func CheckDeleteWhitelistDomain(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWhitelistDomain()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("WhitelistDomain", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteWhitelistDomainTarget is CheckDeleteWhitelistDomain for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteWhitelistDomainTarget(cred *common.Credential, t Target) error {
+	return checkTarget("WhitelistDomain", DELETEPERM, int64(uint32(cred.GetPerm().GetWhitelistDomain())), cred, t)
+}
+
 func CheckCreateWidget(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWidget()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Widget", CREATEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateWidgetTarget is CheckCreateWidget for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateWidgetTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Widget", CREATEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, t)
 }
 
 func CheckReadWidget(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWidget()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Widget", READPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadWidgetTarget is CheckReadWidget for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadWidgetTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Widget", READPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, t)
 }
 
 func CheckUpdateWidget(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWidget()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Widget", UPDATEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateWidgetTarget is CheckUpdateWidget for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateWidgetTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Widget", UPDATEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, t)
 }
 
 func CheckDeleteWidget(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetWidget()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Widget", DELETEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteWidgetTarget is CheckDeleteWidget for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteWidgetTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Widget", DELETEPERM, int64(uint32(cred.GetPerm().GetWidget())), cred, t)
 }
+
 func CheckCreateSubscription(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSubscription()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Subscription", CREATEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateSubscriptionTarget is CheckCreateSubscription for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateSubscriptionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Subscription", CREATEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, t)
 }
 
 func CheckReadSubscription(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSubscription()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Subscription", READPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadSubscriptionTarget is CheckReadSubscription for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadSubscriptionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Subscription", READPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, t)
 }
 
 func CheckUpdateSubscription(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSubscription()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Subscription", UPDATEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateSubscriptionTarget is CheckUpdateSubscription for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateSubscriptionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Subscription", UPDATEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, t)
 }
 
 func CheckDeleteSubscription(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetSubscription()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Subscription", DELETEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteSubscriptionTarget is CheckDeleteSubscription for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteSubscriptionTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Subscription", DELETEPERM, int64(uint32(cred.GetPerm().GetSubscription())), cred, t)
 }
+
 func CheckCreateInvoice(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetInvoice()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Invoice", CREATEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateInvoiceTarget is CheckCreateInvoice for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateInvoiceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Invoice", CREATEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, t)
 }
 
 func CheckReadInvoice(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetInvoice()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Invoice", READPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadInvoiceTarget is CheckReadInvoice for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadInvoiceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Invoice", READPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, t)
 }
 
 func CheckUpdateInvoice(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetInvoice()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Invoice", UPDATEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateInvoiceTarget is CheckUpdateInvoice for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateInvoiceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Invoice", UPDATEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, t)
 }
 
 func CheckDeleteInvoice(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetInvoice()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Invoice", DELETEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteInvoiceTarget is CheckDeleteInvoice for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteInvoiceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Invoice", DELETEPERM, int64(uint32(cred.GetPerm().GetInvoice())), cred, t)
+}
+
 func CheckCreatePaymentMethod(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentMethod()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentMethod", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePaymentMethodTarget is CheckCreatePaymentMethod for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePaymentMethodTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentMethod", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, t)
 }
 
 func CheckReadPaymentMethod(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentMethod()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentMethod", READPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPaymentMethodTarget is CheckReadPaymentMethod for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPaymentMethodTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentMethod", READPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, t)
 }
 
 func CheckUpdatePaymentMethod(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentMethod()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentMethod", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePaymentMethodTarget is CheckUpdatePaymentMethod for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePaymentMethodTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentMethod", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, t)
 }
 
 func CheckDeletePaymentMethod(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentMethod()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentMethod", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeletePaymentMethodTarget is CheckDeletePaymentMethod for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePaymentMethodTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentMethod", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentMethod())), cred, t)
 }
+
 func CheckCreateBill(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetBill()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Bill", CREATEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateBillTarget is CheckCreateBill for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateBillTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Bill", CREATEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, t)
 }
 
 func CheckReadBill(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetBill()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Bill", READPERM, int64(uint32(cred.GetPerm().GetBill())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadBillTarget is CheckReadBill for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadBillTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Bill", READPERM, int64(uint32(cred.GetPerm().GetBill())), cred, t)
 }
 
 func CheckUpdateBill(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetBill()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Bill", UPDATEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateBillTarget is CheckUpdateBill for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateBillTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Bill", UPDATEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, t)
 }
 
 func CheckDeleteBill(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetBill()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Bill", DELETEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteBillTarget is CheckDeleteBill for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteBillTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Bill", DELETEPERM, int64(uint32(cred.GetPerm().GetBill())), cred, t)
 }
+
 func CheckCreatePaymentLog(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentLog()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentLog", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePaymentLogTarget is CheckCreatePaymentLog for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePaymentLogTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentLog", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, t)
 }
 
 func CheckReadPaymentLog(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentLog()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentLog", READPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPaymentLogTarget is CheckReadPaymentLog for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPaymentLogTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentLog", READPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, t)
 }
 
 func CheckUpdatePaymentLog(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentLog()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentLog", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePaymentLogTarget is CheckUpdatePaymentLog for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePaymentLogTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentLog", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, t)
 }
 
 func CheckDeletePaymentLog(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentLog()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentLog", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeletePaymentLogTarget is CheckDeletePaymentLog for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePaymentLogTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentLog", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentLog())), cred, t)
+}
+
 func CheckCreatePaymentComment(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentComment()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentComment", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePaymentCommentTarget is CheckCreatePaymentComment for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePaymentCommentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentComment", CREATEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, t)
 }
 
 func CheckReadPaymentComment(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentComment()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentComment", READPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPaymentCommentTarget is CheckReadPaymentComment for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPaymentCommentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentComment", READPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, t)
 }
 
 func CheckUpdatePaymentComment(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentComment()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentComment", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePaymentCommentTarget is CheckUpdatePaymentComment for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePaymentCommentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentComment", UPDATEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, t)
 }
 
 func CheckDeletePaymentComment(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPaymentComment()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PaymentComment", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeletePaymentCommentTarget is CheckDeletePaymentComment for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePaymentCommentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PaymentComment", DELETEPERM, int64(uint32(cred.GetPerm().GetPaymentComment())), cred, t)
 }
+
 func CheckCreateUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("User", CREATEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateUserTarget is CheckCreateUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("User", CREATEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, t)
 }
 
 func CheckReadUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("User", READPERM, int64(uint32(cred.GetPerm().GetUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadUserTarget is CheckReadUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("User", READPERM, int64(uint32(cred.GetPerm().GetUser())), cred, t)
 }
 
 func CheckUpdateUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("User", UPDATEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateUserTarget is CheckUpdateUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("User", UPDATEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, t)
 }
 
 func CheckDeleteUser(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetUser()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("User", DELETEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteUserTarget is CheckDeleteUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("User", DELETEPERM, int64(uint32(cred.GetPerm().GetUser())), cred, t)
 }
+
 func CheckCreateAutomation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAutomation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Automation", CREATEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAutomationTarget is CheckCreateAutomation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAutomationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Automation", CREATEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, t)
 }
 
 func CheckReadAutomation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAutomation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Automation", READPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAutomationTarget is CheckReadAutomation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAutomationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Automation", READPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, t)
 }
 
 func CheckUpdateAutomation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAutomation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Automation", UPDATEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAutomationTarget is CheckUpdateAutomation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAutomationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Automation", UPDATEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, t)
 }
 
 func CheckDeleteAutomation(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAutomation()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Automation", DELETEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteAutomationTarget is CheckDeleteAutomation for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAutomationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Automation", DELETEPERM, int64(uint32(cred.GetPerm().GetAutomation())), cred, t)
+}
+
 func CheckCreatePing(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPing()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Ping", CREATEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePingTarget is CheckCreatePing for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePingTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Ping", CREATEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, t)
 }
 
 func CheckReadPing(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPing()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Ping", READPERM, int64(uint32(cred.GetPerm().GetPing())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPingTarget is CheckReadPing for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPingTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Ping", READPERM, int64(uint32(cred.GetPerm().GetPing())), cred, t)
 }
 
 func CheckUpdatePing(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPing()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Ping", UPDATEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePingTarget is CheckUpdatePing for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePingTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Ping", UPDATEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, t)
 }
 
 func CheckDeletePing(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPing()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Ping", DELETEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeletePingTarget is CheckDeletePing for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePingTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Ping", DELETEPERM, int64(uint32(cred.GetPerm().GetPing())), cred, t)
+}
+
 func CheckCreateAttribute(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAttribute()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Attribute", CREATEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAttributeTarget is CheckCreateAttribute for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAttributeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Attribute", CREATEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, t)
 }
 
 func CheckReadAttribute(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAttribute()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Attribute", READPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAttributeTarget is CheckReadAttribute for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAttributeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Attribute", READPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, t)
 }
 
 func CheckUpdateAttribute(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAttribute()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Attribute", UPDATEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAttributeTarget is CheckUpdateAttribute for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAttributeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Attribute", UPDATEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, t)
 }
 
 func CheckDeleteAttribute(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAttribute()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Attribute", DELETEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteAttributeTarget is CheckDeleteAttribute for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAttributeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Attribute", DELETEPERM, int64(uint32(cred.GetPerm().GetAttribute())), cred, t)
 }
+
 func CheckCreateAgentNotification(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentNotification()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentNotification", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentNotificationTarget is CheckCreateAgentNotification for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentNotificationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentNotification", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, t)
 }
 
 func CheckReadAgentNotification(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentNotification()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentNotification", READPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentNotificationTarget is CheckReadAgentNotification for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentNotificationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentNotification", READPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, t)
 }
 
 func CheckUpdateAgentNotification(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentNotification()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentNotification", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentNotificationTarget is CheckUpdateAgentNotification for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentNotificationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentNotification", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, t)
 }
 
 func CheckDeleteAgentNotification(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentNotification()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentNotification", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteAgentNotificationTarget is CheckDeleteAgentNotification for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentNotificationTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentNotification", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentNotification())), cred, t)
 }
+
 func CheckCreateConversationExport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationExport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationExport", CREATEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateConversationExportTarget is CheckCreateConversationExport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateConversationExportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationExport", CREATEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, t)
 }
 
 func CheckReadConversationExport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationExport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationExport", READPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadConversationExportTarget is CheckReadConversationExport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadConversationExportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationExport", READPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, t)
 }
 
 func CheckUpdateConversationExport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationExport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationExport", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateConversationExportTarget is CheckUpdateConversationExport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateConversationExportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationExport", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, t)
 }
 
 func CheckDeleteConversationExport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationExport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationExport", DELETEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteConversationExportTarget is CheckDeleteConversationExport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteConversationExportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationExport", DELETEPERM, int64(uint32(cred.GetPerm().GetConversationExport())), cred, t)
+}
+
 func CheckCreateConversationReport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationReport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationReport", CREATEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateConversationReportTarget is CheckCreateConversationReport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateConversationReportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationReport", CREATEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, t)
 }
 
 func CheckReadConversationReport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationReport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationReport", READPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadConversationReportTarget is CheckReadConversationReport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadConversationReportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationReport", READPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, t)
 }
 
 func CheckUpdateConversationReport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationReport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationReport", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateConversationReportTarget is CheckUpdateConversationReport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateConversationReportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationReport", UPDATEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, t)
 }
 
 func CheckDeleteConversationReport(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetConversationReport()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ConversationReport", DELETEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteConversationReportTarget is CheckDeleteConversationReport for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteConversationReportTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ConversationReport", DELETEPERM, int64(uint32(cred.GetPerm().GetConversationReport())), cred, t)
+}
+
 func CheckCreateContent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetContent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Content", CREATEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateContentTarget is CheckCreateContent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateContentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Content", CREATEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, t)
 }
 
 func CheckReadContent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetContent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Content", READPERM, int64(uint32(cred.GetPerm().GetContent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadContentTarget is CheckReadContent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadContentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Content", READPERM, int64(uint32(cred.GetPerm().GetContent())), cred, t)
 }
 
 func CheckUpdateContent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetContent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Content", UPDATEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateContentTarget is CheckUpdateContent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateContentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Content", UPDATEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, t)
 }
 
 func CheckDeleteContent(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetContent()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Content", DELETEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteContentTarget is CheckDeleteContent for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteContentTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Content", DELETEPERM, int64(uint32(cred.GetPerm().GetContent())), cred, t)
 }
+
 func CheckCreatePipeline(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPipeline()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Pipeline", CREATEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePipelineTarget is CheckCreatePipeline for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePipelineTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Pipeline", CREATEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, t)
 }
 
 func CheckReadPipeline(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPipeline()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Pipeline", READPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPipelineTarget is CheckReadPipeline for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPipelineTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Pipeline", READPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, t)
 }
 
 func CheckUpdatePipeline(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPipeline()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Pipeline", UPDATEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePipelineTarget is CheckUpdatePipeline for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePipelineTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Pipeline", UPDATEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, t)
 }
 
 func CheckDeletePipeline(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPipeline()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Pipeline", DELETEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeletePipelineTarget is CheckDeletePipeline for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePipelineTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Pipeline", DELETEPERM, int64(uint32(cred.GetPerm().GetPipeline())), cred, t)
 }
+
 func CheckCreateCurrency(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCurrency()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Currency", CREATEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateCurrencyTarget is CheckCreateCurrency for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateCurrencyTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Currency", CREATEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, t)
 }
 
 func CheckReadCurrency(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCurrency()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Currency", READPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadCurrencyTarget is CheckReadCurrency for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadCurrencyTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Currency", READPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, t)
 }
 
 func CheckUpdateCurrency(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCurrency()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Currency", UPDATEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateCurrencyTarget is CheckUpdateCurrency for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateCurrencyTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Currency", UPDATEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, t)
 }
 
 func CheckDeleteCurrency(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetCurrency()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Currency", DELETEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteCurrencyTarget is CheckDeleteCurrency for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteCurrencyTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Currency", DELETEPERM, int64(uint32(cred.GetPerm().GetCurrency())), cred, t)
+}
+
 func CheckCreateServiceLevelAgreement(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetServiceLevelAgreement()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ServiceLevelAgreement", CREATEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateServiceLevelAgreementTarget is CheckCreateServiceLevelAgreement for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateServiceLevelAgreementTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ServiceLevelAgreement", CREATEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, t)
 }
 
 func CheckReadServiceLevelAgreement(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetServiceLevelAgreement()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("ServiceLevelAgreement", READPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadServiceLevelAgreementTarget is CheckReadServiceLevelAgreement for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadServiceLevelAgreementTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ServiceLevelAgreement", READPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, t)
 }
 
 func CheckUpdateServiceLevelAgreement(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetServiceLevelAgreement()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ServiceLevelAgreement", UPDATEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateServiceLevelAgreementTarget is CheckUpdateServiceLevelAgreement for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateServiceLevelAgreementTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ServiceLevelAgreement", UPDATEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, t)
 }
 
 func CheckDeleteServiceLevelAgreement(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetServiceLevelAgreement()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("ServiceLevelAgreement", DELETEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteServiceLevelAgreementTarget is CheckDeleteServiceLevelAgreement for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteServiceLevelAgreementTarget(cred *common.Credential, t Target) error {
+	return checkTarget("ServiceLevelAgreement", DELETEPERM, int64(uint32(cred.GetPerm().GetServiceLevelAgreement())), cred, t)
 }
+
 func CheckCreateMessageTemplate(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetMessageTemplate()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("MessageTemplate", CREATEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateMessageTemplateTarget is CheckCreateMessageTemplate for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateMessageTemplateTarget(cred *common.Credential, t Target) error {
+	return checkTarget("MessageTemplate", CREATEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, t)
 }
 
 func CheckReadMessageTemplate(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetMessageTemplate()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("MessageTemplate", READPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadMessageTemplateTarget is CheckReadMessageTemplate for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadMessageTemplateTarget(cred *common.Credential, t Target) error {
+	return checkTarget("MessageTemplate", READPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, t)
 }
 
 func CheckUpdateMessageTemplate(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetMessageTemplate()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("MessageTemplate", UPDATEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateMessageTemplateTarget is CheckUpdateMessageTemplate for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateMessageTemplateTarget(cred *common.Credential, t Target) error {
+	return checkTarget("MessageTemplate", UPDATEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, t)
 }
 
 func CheckDeleteMessageTemplate(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetMessageTemplate()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("MessageTemplate", DELETEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteMessageTemplateTarget is CheckDeleteMessageTemplate for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteMessageTemplateTarget(cred *common.Credential, t Target) error {
+	return checkTarget("MessageTemplate", DELETEPERM, int64(uint32(cred.GetPerm().GetMessageTemplate())), cred, t)
 }
+
 func CheckCreateAgentPresence(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPresence()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPresence", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentPresenceTarget is CheckCreateAgentPresence for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentPresenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPresence", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, t)
 }
 
 func CheckReadAgentPresence(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPresence()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPresence", READPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentPresenceTarget is CheckReadAgentPresence for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentPresenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPresence", READPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, t)
 }
 
 func CheckUpdateAgentPresence(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPresence()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPresence", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentPresenceTarget is CheckUpdateAgentPresence for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentPresenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPresence", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, t)
 }
 
 func CheckDeleteAgentPresence(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPresence()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPresence", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteAgentPresenceTarget is CheckDeleteAgentPresence for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentPresenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPresence", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPresence())), cred, t)
+}
+
 func CheckCreateAgentPreference(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPreference()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPreference", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateAgentPreferenceTarget is CheckCreateAgentPreference for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateAgentPreferenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPreference", CREATEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, t)
 }
 
 func CheckReadAgentPreference(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPreference()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPreference", READPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadAgentPreferenceTarget is CheckReadAgentPreference for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadAgentPreferenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPreference", READPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, t)
 }
 
 func CheckUpdateAgentPreference(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPreference()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPreference", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateAgentPreferenceTarget is CheckUpdateAgentPreference for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateAgentPreferenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPreference", UPDATEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, t)
 }
 
 func CheckDeleteAgentPreference(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetAgentPreference()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("AgentPreference", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, Target{AccountId: accid, Owners: agids})
 }
+
+// CheckDeleteAgentPreferenceTarget is CheckDeleteAgentPreference for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteAgentPreferenceTarget(cred *common.Credential, t Target) error {
+	return checkTarget("AgentPreference", DELETEPERM, int64(uint32(cred.GetPerm().GetAgentPreference())), cred, t)
+}
+
 func CheckCreatePromotionCode(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPromotionCode()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PromotionCode", CREATEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreatePromotionCodeTarget is CheckCreatePromotionCode for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreatePromotionCodeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PromotionCode", CREATEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, t)
 }
 
 func CheckReadPromotionCode(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPromotionCode()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("PromotionCode", READPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadPromotionCodeTarget is CheckReadPromotionCode for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadPromotionCodeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PromotionCode", READPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, t)
 }
 
 func CheckUpdatePromotionCode(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPromotionCode()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PromotionCode", UPDATEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdatePromotionCodeTarget is CheckUpdatePromotionCode for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdatePromotionCodeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PromotionCode", UPDATEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, t)
 }
 
 func CheckDeletePromotionCode(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetPromotionCode()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("PromotionCode", DELETEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeletePromotionCodeTarget is CheckDeletePromotionCode for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeletePromotionCodeTarget(cred *common.Credential, t Target) error {
+	return checkTarget("PromotionCode", DELETEPERM, int64(uint32(cred.GetPerm().GetPromotionCode())), cred, t)
 }
+
 func CheckCreateReferral(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetReferral()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(CREATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Referral", CREATEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckCreateReferralTarget is CheckCreateReferral for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckCreateReferralTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Referral", CREATEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, t)
 }
 
 func CheckReadReferral(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetReferral()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(READPERM, callerperm, ismine, isaccount)
+	return checkTarget("Referral", READPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckReadReferralTarget is CheckReadReferral for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckReadReferralTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Referral", READPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, t)
 }
 
 func CheckUpdateReferral(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetReferral()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(UPDATEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Referral", UPDATEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckUpdateReferralTarget is CheckUpdateReferral for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckUpdateReferralTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Referral", UPDATEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, t)
 }
 
 func CheckDeleteReferral(cred *common.Credential, accid string, agids ...string) error {
-	callerperm := cred.GetPerm().GetReferral()
-	ismine := cred.GetAccountId() == accid && contains(cred.GetIssuer(), agids)
-	isaccount := cred.GetAccountId() == accid
-	return checkPerm(DELETEPERM, callerperm, ismine, isaccount)
+	return checkTarget("Referral", DELETEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckDeleteReferralTarget is CheckDeleteReferral for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckDeleteReferralTarget(cred *common.Credential, t Target) error {
+	return checkTarget("Referral", DELETEPERM, int64(uint32(cred.GetPerm().GetReferral())), cred, t)
+}
+
+// extraActions lists the letters of the actions beyond create, read,
+// update and delete, the n-th letter uses the n-th bit above crud
+const extraActions = "e"
+
+// extraActionNames lists the names of the extra actions, in the order
+// of extraActions
+var extraActionNames = []string{"export"}
+
+const EXPORTPERM int32 = 0x10
+
+func CheckExportUser(cred *common.Credential, accid string, agids ...string) error {
+	return checkTarget("User", EXPORTPERM, int64(uint32(cred.GetPerm().GetUser())), cred, Target{AccountId: accid, Owners: agids})
+}
+
+// CheckExportUserTarget is CheckExportUser for a resource which may be owned by agent
+// groups or guarded by conditions
+func CheckExportUserTarget(cred *common.Credential, t Target) error {
+	return checkTarget("User", EXPORTPERM, int64(uint32(cred.GetPerm().GetUser())), cred, t)
 }
 
 func pInt32(i int32) *int32 {
@@ -1100,3 +1616,273 @@ func IntersectPermission(a, b *common.Permission) *common.Permission {
 		Referral:              a.GetReferral() & b.GetReferral(),
 	}
 }
+
+// Resources describes every field of common.Permission
+var Resources = []Resource{
+	{
+		Name:      "Account",
+		SnakeName: "account",
+		Number:    2,
+		Get:       (*common.Permission).GetAccount,
+		Set:       func(p *common.Permission, v int32) { p.Account = v },
+	},
+	{
+		Name:      "Agent",
+		SnakeName: "agent",
+		Number:    3,
+		Get:       (*common.Permission).GetAgent,
+		Set:       func(p *common.Permission, v int32) { p.Agent = v },
+	},
+	{
+		Name:      "AgentPassword",
+		SnakeName: "agent_password",
+		Number:    4,
+		Get:       (*common.Permission).GetAgentPassword,
+		Set:       func(p *common.Permission, v int32) { p.AgentPassword = v },
+	},
+	{
+		Name:      "Permission",
+		SnakeName: "permission",
+		Number:    5,
+		Get:       (*common.Permission).GetPermission,
+		Set:       func(p *common.Permission, v int32) { p.Permission = v },
+	},
+	{
+		Name:      "AgentGroup",
+		SnakeName: "agent_group",
+		Number:    6,
+		Get:       (*common.Permission).GetAgentGroup,
+		Set:       func(p *common.Permission, v int32) { p.AgentGroup = v },
+	},
+	{
+		Name:      "Segmentation",
+		SnakeName: "segmentation",
+		Number:    7,
+		Get:       (*common.Permission).GetSegmentation,
+		Set:       func(p *common.Permission, v int32) { p.Segmentation = v },
+	},
+	{
+		Name:      "Client",
+		SnakeName: "client",
+		Number:    8,
+		Get:       (*common.Permission).GetClient,
+		Set:       func(p *common.Permission, v int32) { p.Client = v },
+	},
+	{
+		Name:      "Rule",
+		SnakeName: "rule",
+		Number:    9,
+		Get:       (*common.Permission).GetRule,
+		Set:       func(p *common.Permission, v int32) { p.Rule = v },
+	},
+	{
+		Name:      "Conversation",
+		SnakeName: "conversation",
+		Number:    10,
+		Get:       (*common.Permission).GetConversation,
+		Set:       func(p *common.Permission, v int32) { p.Conversation = v },
+	},
+	{
+		Name:      "Integration",
+		SnakeName: "integration",
+		Number:    11,
+		Get:       (*common.Permission).GetIntegration,
+		Set:       func(p *common.Permission, v int32) { p.Integration = v },
+	},
+	{
+		Name:      "CannedResponse",
+		SnakeName: "canned_response",
+		Number:    12,
+		Get:       (*common.Permission).GetCannedResponse,
+		Set:       func(p *common.Permission, v int32) { p.CannedResponse = v },
+	},
+	{
+		Name:      "Tag",
+		SnakeName: "tag",
+		Number:    13,
+		Get:       (*common.Permission).GetTag,
+		Set:       func(p *common.Permission, v int32) { p.Tag = v },
+	},
+	{
+		Name:      "WhitelistIp",
+		SnakeName: "whitelist_ip",
+		Number:    14,
+		Get:       (*common.Permission).GetWhitelistIp,
+		Set:       func(p *common.Permission, v int32) { p.WhitelistIp = v },
+	},
+	{
+		Name:      "WhitelistUser",
+		SnakeName: "whitelist_user",
+		Number:    15,
+		Get:       (*common.Permission).GetWhitelistUser,
+		Set:       func(p *common.Permission, v int32) { p.WhitelistUser = v },
+	},
+	{
+		Name:      "WhitelistDomain",
+		SnakeName: "whitelist_domain",
+		Number:    16,
+		Get:       (*common.Permission).GetWhitelistDomain,
+		Set:       func(p *common.Permission, v int32) { p.WhitelistDomain = v },
+	},
+	{
+		Name:      "Widget",
+		SnakeName: "widget",
+		Number:    17,
+		Get:       (*common.Permission).GetWidget,
+		Set:       func(p *common.Permission, v int32) { p.Widget = v },
+	},
+	{
+		Name:      "Subscription",
+		SnakeName: "subscription",
+		Number:    18,
+		Get:       (*common.Permission).GetSubscription,
+		Set:       func(p *common.Permission, v int32) { p.Subscription = v },
+	},
+	{
+		Name:      "Invoice",
+		SnakeName: "invoice",
+		Number:    19,
+		Get:       (*common.Permission).GetInvoice,
+		Set:       func(p *common.Permission, v int32) { p.Invoice = v },
+	},
+	{
+		Name:      "PaymentMethod",
+		SnakeName: "payment_method",
+		Number:    20,
+		Get:       (*common.Permission).GetPaymentMethod,
+		Set:       func(p *common.Permission, v int32) { p.PaymentMethod = v },
+	},
+	{
+		Name:      "Bill",
+		SnakeName: "bill",
+		Number:    21,
+		Get:       (*common.Permission).GetBill,
+		Set:       func(p *common.Permission, v int32) { p.Bill = v },
+	},
+	{
+		Name:      "PaymentLog",
+		SnakeName: "payment_log",
+		Number:    22,
+		Get:       (*common.Permission).GetPaymentLog,
+		Set:       func(p *common.Permission, v int32) { p.PaymentLog = v },
+	},
+	{
+		Name:      "PaymentComment",
+		SnakeName: "payment_comment",
+		Number:    23,
+		Get:       (*common.Permission).GetPaymentComment,
+		Set:       func(p *common.Permission, v int32) { p.PaymentComment = v },
+	},
+	{
+		Name:      "User",
+		SnakeName: "user",
+		Number:    24,
+		Get:       (*common.Permission).GetUser,
+		Set:       func(p *common.Permission, v int32) { p.User = v },
+	},
+	{
+		Name:      "Automation",
+		SnakeName: "automation",
+		Number:    25,
+		Get:       (*common.Permission).GetAutomation,
+		Set:       func(p *common.Permission, v int32) { p.Automation = v },
+	},
+	{
+		Name:      "Ping",
+		SnakeName: "ping",
+		Number:    26,
+		Get:       (*common.Permission).GetPing,
+		Set:       func(p *common.Permission, v int32) { p.Ping = v },
+	},
+	{
+		Name:      "Attribute",
+		SnakeName: "attribute",
+		Number:    27,
+		Get:       (*common.Permission).GetAttribute,
+		Set:       func(p *common.Permission, v int32) { p.Attribute = v },
+	},
+	{
+		Name:      "AgentNotification",
+		SnakeName: "agent_notification",
+		Number:    28,
+		Get:       (*common.Permission).GetAgentNotification,
+		Set:       func(p *common.Permission, v int32) { p.AgentNotification = v },
+	},
+	{
+		Name:      "ConversationExport",
+		SnakeName: "conversation_export",
+		Number:    29,
+		Get:       (*common.Permission).GetConversationExport,
+		Set:       func(p *common.Permission, v int32) { p.ConversationExport = v },
+	},
+	{
+		Name:      "ConversationReport",
+		SnakeName: "conversation_report",
+		Number:    30,
+		Get:       (*common.Permission).GetConversationReport,
+		Set:       func(p *common.Permission, v int32) { p.ConversationReport = v },
+	},
+	{
+		Name:      "Content",
+		SnakeName: "content",
+		Number:    31,
+		Get:       (*common.Permission).GetContent,
+		Set:       func(p *common.Permission, v int32) { p.Content = v },
+	},
+	{
+		Name:      "Pipeline",
+		SnakeName: "pipeline",
+		Number:    32,
+		Get:       (*common.Permission).GetPipeline,
+		Set:       func(p *common.Permission, v int32) { p.Pipeline = v },
+	},
+	{
+		Name:      "Currency",
+		SnakeName: "currency",
+		Number:    33,
+		Get:       (*common.Permission).GetCurrency,
+		Set:       func(p *common.Permission, v int32) { p.Currency = v },
+	},
+	{
+		Name:      "ServiceLevelAgreement",
+		SnakeName: "service_level_agreement",
+		Number:    34,
+		Get:       (*common.Permission).GetServiceLevelAgreement,
+		Set:       func(p *common.Permission, v int32) { p.ServiceLevelAgreement = v },
+	},
+	{
+		Name:      "MessageTemplate",
+		SnakeName: "message_template",
+		Number:    35,
+		Get:       (*common.Permission).GetMessageTemplate,
+		Set:       func(p *common.Permission, v int32) { p.MessageTemplate = v },
+	},
+	{
+		Name:      "AgentPresence",
+		SnakeName: "agent_presence",
+		Number:    36,
+		Get:       (*common.Permission).GetAgentPresence,
+		Set:       func(p *common.Permission, v int32) { p.AgentPresence = v },
+	},
+	{
+		Name:      "AgentPreference",
+		SnakeName: "agent_preference",
+		Number:    37,
+		Get:       (*common.Permission).GetAgentPreference,
+		Set:       func(p *common.Permission, v int32) { p.AgentPreference = v },
+	},
+	{
+		Name:      "PromotionCode",
+		SnakeName: "promotion_code",
+		Number:    38,
+		Get:       (*common.Permission).GetPromotionCode,
+		Set:       func(p *common.Permission, v int32) { p.PromotionCode = v },
+	},
+	{
+		Name:      "Referral",
+		SnakeName: "referral",
+		Number:    39,
+		Get:       (*common.Permission).GetReferral,
+		Set:       func(p *common.Permission, v int32) { p.Referral = v },
+	},
+}
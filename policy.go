@@ -0,0 +1,158 @@
+package perm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/subiz/header/common"
+)
+
+// Policy is an immutable snapshot of Base and the scopes. Every evaluation
+// loads the current snapshot once, so a concurrent update is never seen half
+// applied. Use RegisterScope and SetBase to replace the current policy
+type Policy struct {
+	base     *common.Permission
+	defs     map[string]ScopeDef
+	scopes   map[string]ScopeSet
+	compiled *compiledScopes
+}
+
+// NewPolicy validates and compiles a policy. base and defs are copied, later
+// changes to them don't affect the policy. Only policies with at most 64
+// scopes are compiled, see Access
+func NewPolicy(base *common.Permission, defs map[string]ScopeDef) (*Policy, error) {
+	p := &Policy{
+		base: &common.Permission{},
+		defs: make(map[string]ScopeDef, len(defs)),
+	}
+	if base != nil {
+		p.base = proto.Clone(base).(*common.Permission)
+	}
+	for name, def := range defs {
+		def.Parents = append([]string(nil), def.Parents...)
+		p.defs[name] = def
+	}
+
+	var err error
+	if p.scopes, err = ResolveScopes(p.defs); err != nil {
+		return nil, err
+	}
+
+	// Access falls back to evaluating the scope sets when there are too
+	// many scopes to compile
+	if len(p.scopes) <= maxCompiledScopes {
+		if p.compiled, err = compileScopeSets(p.scopes); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// access implements Access
+func (p *Policy) access(scopes []string, perm string) bool {
+	if p.compiled != nil {
+		return p.compiled.access(scopes, perm)
+	}
+
+	available := ScopeSet{}
+	for _, scope := range scopes {
+		available = available.Union(p.scopes[strings.TrimSpace(scope)])
+	}
+	return ParseScopeSet(perm).SubsetOf(available)
+}
+
+func mustNewPolicy(base *common.Permission, defs map[string]ScopeDef) *Policy {
+	p, err := NewPolicy(base, defs)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Base returns a copy of the biggest possible permission that is valid
+func (p *Policy) Base() *common.Permission {
+	return proto.Clone(p.base).(*common.Permission)
+}
+
+// Scope returns a copy of the actions scope allows, including the inherited
+// ones
+func (p *Policy) Scope(name string) (ScopeSet, bool) {
+	set, ok := p.scopes[name]
+	if !ok {
+		return nil, false
+	}
+	return set.Union(nil), true
+}
+
+// ScopeDef returns a copy of the declaration of scope name
+func (p *Policy) ScopeDef(name string) (ScopeDef, bool) {
+	def, ok := p.defs[name]
+	def.Parents = append([]string(nil), def.Parents...)
+	return def, ok
+}
+
+// ScopeNames returns the names of the scopes in ascending order
+func (p *Policy) ScopeNames() []string {
+	names := make([]string, 0, len(p.defs))
+	for name := range p.defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithScope returns a new policy where scope name is declared as def
+func (p *Policy) WithScope(name string, def ScopeDef) (*Policy, error) {
+	if name == "" {
+		return nil, fmt.Errorf("scope name is required")
+	}
+
+	defs := make(map[string]ScopeDef, len(p.defs)+1)
+	for k, v := range p.defs {
+		defs[k] = v
+	}
+	defs[name] = def
+	return NewPolicy(p.base, defs)
+}
+
+// WithBase returns a new policy where Base is base
+func (p *Policy) WithBase(base *common.Permission) (*Policy, error) {
+	return NewPolicy(base, p.defs)
+}
+
+// defaultPolicy is built from the declarations of this package
+var defaultPolicy = mustNewPolicy(&Base, scopeGraph)
+
+var policy = newHolder(defaultPolicy)
+
+// CurrentPolicy returns the policy checks are evaluated against
+func CurrentPolicy() *Policy {
+	return policy.load().(*Policy)
+}
+
+// SetPolicy replaces the current policy, it is safe to call while checks are
+// running. A nil p restores the default policy
+func SetPolicy(p *Policy) {
+	if p == nil {
+		p = defaultPolicy
+	}
+	policy.store(p)
+}
+
+// RegisterScope declares scope name, replacing any previous declaration. It
+// fails, leaving the current policy untouched, if the scope references an
+// undefined scope or makes a cycle
+func RegisterScope(name string, def ScopeDef) error {
+	return policy.update(func(cur interface{}) (interface{}, error) {
+		return cur.(*Policy).WithScope(name, def)
+	})
+}
+
+// SetBase replaces Base of the current policy
+func SetBase(base *common.Permission) error {
+	return policy.update(func(cur interface{}) (interface{}, error) {
+		return cur.(*Policy).WithBase(base)
+	})
+}
@@ -1,6 +1,38 @@
 package perm
 
-import "github.com/subiz/header/common"
+import (
+	"sort"
+
+	"github.com/subiz/header/common"
+)
+
+// roles maps the name of each predefined role to the function building its
+// permission
+var roles = map[string]func() *common.Permission{
+	"agent":           GetAgentPerm,
+	"account_setting": GetAccountSettingPerm,
+	"account_manage":  GetAccountManagePerm,
+	"owner":           GetOwnerPerm,
+}
+
+// Role returns the permission of the predefined role name
+func Role(name string) (*common.Permission, bool) {
+	role, ok := roles[name]
+	if !ok {
+		return nil, false
+	}
+	return role(), true
+}
+
+// RoleNames returns the names of the predefined roles in ascending order
+func RoleNames() []string {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
 
 func GetAccountSettingPerm() *common.Permission {
 	return Merge(GetAgentPerm(), &common.Permission{
@@ -0,0 +1,211 @@
+package perm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/subiz/errors"
+	"github.com/subiz/header/common"
+	"github.com/subiz/perm/core"
+)
+
+// PrincipalType tells what kind of identity a credential belongs to
+type PrincipalType int
+
+const (
+	// AgentPrincipal is a human agent, identified by cred.GetIssuer()
+	AgentPrincipal PrincipalType = iota
+	// ServiceAccountPrincipal is an automation, a webhook or an integration
+	ServiceAccountPrincipal
+	// APIKeyPrincipal is a key issued to a third party
+	APIKeyPrincipal
+)
+
+func (t PrincipalType) String() string {
+	switch t {
+	case AgentPrincipal:
+		return "agent"
+	case ServiceAccountPrincipal:
+		return "service account"
+	case APIKeyPrincipal:
+		return "api key"
+	}
+	return fmt.Sprintf("PrincipalType(%d)", int(t))
+}
+
+// ServiceAccount is a non-human principal. It never owns resources nor
+// belongs to agent groups, so the u: and g: levels of its permission are
+// ignored, only a: and s: grants apply. Its credentials are checked with at
+// most Perm, whatever permission they carry
+type ServiceAccount struct {
+	Id        string        // issuer of the credentials of the principal
+	Type      PrincipalType // ServiceAccountPrincipal or APIKeyPrincipal
+	AccountId string        // when set, the principal can only act in this account, even with s:
+	ExpireAt  time.Time     // zero means the principal never expires
+	Perm      *common.Permission
+}
+
+// Credential returns the credential of the principal for account accid
+func (s *ServiceAccount) Credential(accid string) *common.Credential {
+	return &common.Credential{AccountId: accid, Issuer: s.Id, Perm: clonePermission(s.Perm)}
+}
+
+func clonePermission(p *common.Permission) *common.Permission {
+	if p == nil {
+		return &common.Permission{}
+	}
+	return proto.Clone(p).(*common.Permission)
+}
+
+var serviceAccounts = newHolder(map[string]*ServiceAccount{})
+
+// RegisterServiceAccount makes credentials issued by s.Id be checked as s,
+// replacing any previous principal with the same id, credentials already
+// issued included. Ids must not collide with agent ids. s.Perm is copied
+func RegisterServiceAccount(s ServiceAccount) error {
+	if s.Id == "" {
+		return fmt.Errorf("service account id is required")
+	}
+
+	if s.Type != ServiceAccountPrincipal && s.Type != APIKeyPrincipal {
+		return fmt.Errorf("service account %s: invalid type %s", s.Id, s.Type)
+	}
+
+	s.Perm = clonePermission(s.Perm)
+	return serviceAccounts.update(func(cur interface{}) (interface{}, error) {
+		m := make(map[string]*ServiceAccount, len(cur.(map[string]*ServiceAccount))+1)
+		for id, sa := range cur.(map[string]*ServiceAccount) {
+			m[id] = sa
+		}
+		m[s.Id] = &s
+		return m, nil
+	})
+}
+
+// RemoveServiceAccount unregisters the principal with id, credentials issued
+// by it are checked as an agent's again
+func RemoveServiceAccount(id string) {
+	serviceAccounts.update(func(cur interface{}) (interface{}, error) {
+		m := make(map[string]*ServiceAccount, len(cur.(map[string]*ServiceAccount)))
+		for k, sa := range cur.(map[string]*ServiceAccount) {
+			if k != id {
+				m[k] = sa
+			}
+		}
+		return m, nil
+	})
+}
+
+func lookupServiceAccount(id string) *ServiceAccount {
+	return serviceAccounts.load().(map[string]*ServiceAccount)[id]
+}
+
+// PrincipalOf returns the type of the principal cred belongs to
+func PrincipalOf(cred *common.Credential) PrincipalType {
+	if sa := lookupServiceAccount(cred.GetIssuer()); sa != nil {
+		return sa.Type
+	}
+	return AgentPrincipal
+}
+
+// relation computes how the caller relates to t, an instance of resource:
+// whether it owns t, whether one of its groups owns t and whether both are in
+// the same account. It fails for expired principals and for principals
+// pinned to another account
+func relation(cred *common.Credential, resource string, t Target) (ismine, ingroup, isaccount bool, err error) {
+	isaccount = cred.GetAccountId() == t.AccountId
+	sa := lookupServiceAccount(cred.GetIssuer())
+	if sa == nil {
+		ismine = isaccount && isOwner(cred, resource, t.AccountId, t.Owners)
+		return ismine, isaccount && t.InGroup(), isaccount, nil
+	}
+
+	if !sa.ExpireAt.IsZero() && Now().After(sa.ExpireAt) {
+		return false, false, false, errors.New(400, errors.E_access_deny, sa.Type.String()+" "+sa.Id+" has expired")
+	}
+
+	// pinning bounds every level, s: included
+	if sa.AccountId != "" && sa.AccountId != t.AccountId {
+		return false, false, false, errors.New(400, errors.E_access_deny, sa.Type.String()+" "+sa.Id+" is pinned to account "+sa.AccountId)
+	}
+	return false, false, isaccount, nil
+}
+
+// credentialPrincipal adapts a credential to core.Principal, checking it as
+// the Check functions do
+type credentialPrincipal struct{ cred *common.Credential }
+
+// AsPrincipal returns cred as a core.Principal, for callers evaluating
+// permissions with package core directly. core.Check on it applies the
+// current OwnershipResolver, the service account rules and the conditions,
+// like the Check functions
+func AsPrincipal(cred *common.Credential) core.Principal {
+	return credentialPrincipal{cred}
+}
+
+func (p credentialPrincipal) AccountId() string { return p.cred.GetAccountId() }
+
+func (p credentialPrincipal) Issuer() string { return p.cred.GetIssuer() }
+
+// Perm returns the field of the credential's permission named resource,
+// either in snake case or in camel case, 0 for unknown resources
+func (p credentialPrincipal) Perm(resource string) int64 {
+	r, err := FindResource(resource)
+	if err != nil {
+		return 0
+	}
+	return boundPerm(p.cred, resource, int64(uint32(r.Get(p.cred.GetPerm()))))
+}
+
+// Relate implements core.Relater, resource is passed to the ownership
+// resolver by its Go name
+func (p credentialPrincipal) Relate(resource string, t core.Target) (ismine, ingroup, sameaccount bool, err error) {
+	if r, err := FindResource(resource); err == nil {
+		resource = r.Name
+	}
+	return relation(p.cred, resource, t)
+}
+
+// Guard implements core.Guard, the conditions attached to the action must
+// hold
+func (p credentialPrincipal) Guard(resource string, required int32, t core.Target) error {
+	r, err := FindResource(resource)
+	if err != nil {
+		return nil
+	}
+
+	if name, err := checkConditions(r.Name, required, t.Attributes); err != nil {
+		return errors.New(400, errors.E_access_deny, "condition "+name+" not met: "+err.Error())
+	}
+	return nil
+}
+
+// fixedPrincipal is a credential with permission perm on the checked
+// resource, e.g. a V2 permission or a field the Check functions already read
+type fixedPrincipal struct {
+	credentialPrincipal
+	perm int64
+}
+
+func (p fixedPrincipal) Perm(resource string) int64 { return boundPerm(p.cred, resource, p.perm) }
+
+// boundPerm limits perm, the permission of cred on resource in either V1 or
+// V2 encoding, to the permission of the service account issuing cred, if
+// any
+func boundPerm(cred *common.Credential, resource string, perm int64) int64 {
+	sa := lookupServiceAccount(cred.GetIssuer())
+	if sa == nil {
+		return perm
+	}
+
+	r, err := FindResource(resource)
+	if err != nil {
+		return 0
+	}
+
+	if Version(perm) == V2 {
+		return perm & ToV2(r.Get(sa.Perm))
+	}
+	return perm & int64(uint32(r.Get(sa.Perm)))
+}
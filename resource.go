@@ -0,0 +1,47 @@
+package perm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/subiz/header/common"
+)
+
+// Resource describes a field of common.Permission, the table of all resources
+// (Resources) is generated by cli/gen.go
+type Resource struct {
+	Name      string // Go name, e.g. WhitelistIp
+	SnakeName string // protobuf name, e.g. whitelist_ip
+	Number    int32  // protobuf field number
+	Get       func(p *common.Permission) int32
+	Set       func(p *common.Permission, v int32)
+}
+
+// Base returns the value of the field in Base of the current policy
+func (r *Resource) Base() int32 { return r.Get(CurrentPolicy().base) }
+
+// resourceIndex maps the names of Resources, in camel case, in snake case
+// and in lower case without underscores, to them
+var resourceIndex = func() map[string]*Resource {
+	index := make(map[string]*Resource, 3*len(Resources))
+	for i := range Resources {
+		r := &Resources[i]
+		index[r.Name] = r
+		index[r.SnakeName] = r
+		index[strings.ToLower(r.Name)] = r
+	}
+	return index
+}()
+
+// FindResource returns the resource named name, either in snake case
+// (agent_group) or in camel case (AgentGroup)
+func FindResource(name string) (*Resource, error) {
+	if r, ok := resourceIndex[name]; ok {
+		return r, nil
+	}
+
+	if r, ok := resourceIndex[strings.ToLower(strings.Replace(name, "_", "", -1))]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown resource %q", name)
+}
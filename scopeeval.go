@@ -0,0 +1,159 @@
+package perm
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// compiledScopes is the scopes of a policy compiled for Access, which runs
+// on every request. Each scope is a bit of a mask, each exact resource has
+// the actions of every scope, so evaluating an entry is a map lookup and a
+// few bit operations
+type compiledScopes struct {
+	scopes    map[string]uint64  // scope name => its bit
+	resources map[string][]uint8 // exact resource => actions of the n-th scope
+	patterns  []compiledPattern  // entries ending with *
+}
+
+type compiledPattern struct {
+	pattern string
+	scopes  uint64 // scopes having the pattern
+	actions []uint8
+}
+
+// maxCompiledScopes is the number of bits of a scope mask
+const maxCompiledScopes = 64
+
+// compileScopeSets compiles sets, at most maxCompiledScopes of them
+func compileScopeSets(sets map[string]ScopeSet) (*compiledScopes, error) {
+	if len(sets) > maxCompiledScopes {
+		return nil, fmt.Errorf("too many scopes: %d, the limit is %d", len(sets), maxCompiledScopes)
+	}
+
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c := &compiledScopes{
+		scopes:    make(map[string]uint64, len(sets)),
+		resources: make(map[string][]uint8),
+	}
+	patterns := make(map[string]*compiledPattern)
+	for i, name := range names {
+		c.scopes[name] = 1 << uint(i)
+		for _, r := range sets[name].Resources() {
+			if !strings.HasSuffix(r, "*") {
+				if c.resources[r] == nil {
+					c.resources[r] = make([]uint8, len(names))
+				}
+				c.resources[r][i] = sets[name][r]
+				continue
+			}
+
+			if patterns[r] == nil {
+				patterns[r] = &compiledPattern{pattern: r, actions: make([]uint8, len(names))}
+			}
+			patterns[r].scopes |= 1 << uint(i)
+			patterns[r].actions[i] = sets[name][r]
+		}
+	}
+
+	for _, r := range sortedKeys(patterns) {
+		c.patterns = append(c.patterns, *patterns[r])
+	}
+	return c, nil
+}
+
+func sortedKeys(m map[string]*compiledPattern) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// mask returns the bits of scopes, unknown scopes are ignored
+func (c *compiledScopes) mask(scopes []string) uint64 {
+	mask := uint64(0)
+	for _, scope := range scopes {
+		mask |= c.scopes[strings.TrimSpace(scope)]
+	}
+	return mask
+}
+
+// allowed returns the actions the scopes of mask allow on resource, like
+// ScopeSet.covering
+func (c *compiledScopes) allowed(mask uint64, resource string) uint8 {
+	out := uint8(0)
+	// the resource itself and its parents, a pattern is only covered by
+	// the parents of its prefix
+	name := resource
+	if strings.HasSuffix(name, "*") {
+		name = strings.TrimSuffix(name, "*")
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			name = name[:i]
+		} else {
+			name = ""
+		}
+	}
+
+	for name != "" {
+		if actions, ok := c.resources[name]; ok {
+			for m := mask; m != 0; m &= m - 1 {
+				out |= actions[bits.TrailingZeros64(m)]
+			}
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	for _, p := range c.patterns {
+		if p.scopes&mask == 0 || !covers(p.pattern, resource) {
+			continue
+		}
+		for m := p.scopes & mask; m != 0; m &= m - 1 {
+			out |= p.actions[bits.TrailingZeros64(m)]
+		}
+	}
+	return out
+}
+
+// access is Access without allocations, perm is scanned in place
+func (c *compiledScopes) access(scopes []string, perm string) bool {
+	mask := c.mask(scopes)
+	for len(perm) > 0 {
+		end := strings.IndexAny(perm, " ;,\n")
+		if end < 0 {
+			end = len(perm)
+		}
+		item := strings.TrimSpace(perm[:end])
+		perm = perm[end:]
+		if len(perm) > 0 {
+			perm = perm[1:]
+		}
+
+		colon := strings.IndexByte(item, ':')
+		if colon < 0 || strings.IndexByte(item[colon+1:], ':') >= 0 {
+			continue
+		}
+
+		required := scopeBits(item[colon+1:])
+		if required == 0 {
+			continue
+		}
+
+		if required&^c.allowed(mask, item[:colon]) != 0 {
+			return false
+		}
+	}
+	return true
+}
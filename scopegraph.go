@@ -0,0 +1,124 @@
+package perm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScopeDef declares a scope: the scopes it inherits and the entries it adds,
+// e.g. {Parents: []string{"agent"}, Entries: "tag:w rule:w"}, and how it is
+// presented to users, see DescribeScope
+type ScopeDef struct {
+	Parents []string
+	Entries string
+
+	Title       string
+	Description string
+	Sensitivity Sensitivity // must not be lower than the parents'
+}
+
+// ResolveScopes resolves the inheritance of defs, the result maps each scope
+// to its entries joined with the entries of all its ancestors. It fails when
+// a scope inherits an undefined scope or itself, directly or not
+func ResolveScopes(defs map[string]ScopeDef) (map[string]ScopeSet, error) {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make(map[string]ScopeSet, len(defs))
+	visiting := make(map[string]bool)
+	var resolve func(name string, path []string) (ScopeSet, error)
+	resolve = func(name string, path []string) (ScopeSet, error) {
+		if set, ok := out[name]; ok {
+			return set, nil
+		}
+
+		path = append(path, name)
+		if visiting[name] {
+			return nil, fmt.Errorf("scope cycle: %s", strings.Join(path, " -> "))
+		}
+		visiting[name] = true
+
+		set := ParseScopeSet(defs[name].Entries)
+		for _, parent := range defs[name].Parents {
+			if _, ok := defs[parent]; !ok {
+				return nil, fmt.Errorf("scope %s: undefined parent %q", name, parent)
+			}
+
+			pset, err := resolve(parent, path)
+			if err != nil {
+				return nil, err
+			}
+			set = set.Union(pset)
+		}
+
+		visiting[name] = false
+		out[name] = set
+		return set, nil
+	}
+
+	for _, name := range names {
+		if _, err := resolve(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// scopeClosure returns name followed by all its ancestors in defs, each
+// once, parents before grandparents. defs must be resolvable
+func scopeClosure(defs map[string]ScopeDef, name string) []string {
+	out := []string{name}
+	seen := map[string]bool{name: true}
+	for i := 0; i < len(out); i++ {
+		for _, parent := range defs[out[i]].Parents {
+			if !seen[parent] {
+				seen[parent] = true
+				out = append(out, parent)
+			}
+		}
+	}
+	return out
+}
+
+// ScopesIncluding returns, in ascending order, the scopes which inherit
+// scope, directly or not
+func ScopesIncluding(scope string) ([]string, error) {
+	defs := CurrentPolicy().defs
+	if _, ok := defs[scope]; !ok {
+		return nil, fmt.Errorf("undefined scope %q", scope)
+	}
+
+	out := make([]string, 0)
+	for name := range defs {
+		if name == scope {
+			continue
+		}
+
+		for _, ancestor := range scopeClosure(defs, name)[1:] {
+			if ancestor == scope {
+				out = append(out, name)
+				break
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ScopesGranting returns, in ascending order, the scopes which allow all the
+// entries of perm, e.g. "tag:w"
+func ScopesGranting(perm string) []string {
+	want := ParseScopeSet(perm)
+	out := make([]string, 0)
+	for name, set := range CurrentPolicy().scopes {
+		if want.SubsetOf(set) {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
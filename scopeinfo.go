@@ -0,0 +1,93 @@
+package perm
+
+import "fmt"
+
+// Sensitivity rates how much harm a token holding a scope could do, consent
+// screens warn more loudly about higher levels
+type Sensitivity int
+
+const (
+	SensitivityLow Sensitivity = iota
+	SensitivityMedium
+	SensitivityHigh
+)
+
+func (s Sensitivity) String() string {
+	switch s {
+	case SensitivityLow:
+		return "low"
+	case SensitivityMedium:
+		return "medium"
+	case SensitivityHigh:
+		return "high"
+	}
+	return fmt.Sprintf("Sensitivity(%d)", int(s))
+}
+
+// ScopeInfo describes a scope for consent screens and API docs
+type ScopeInfo struct {
+	Name        string   `json:"name"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Sensitivity string   `json:"sensitivity"`
+	Parents     []string `json:"parents,omitempty"`
+	Resources   []string `json:"resources"` // resolved entries, e.g. conversation:wr
+}
+
+// Translator localises the text of a scope. key is scope.<name>.title or
+// scope.<name>.description, fallback is the English text. It returns the
+// text in language lang, or fallback when there is no translation
+type Translator func(lang, key, fallback string) string
+
+var translator = newHolder(Translator(untranslated))
+
+func untranslated(lang, key, fallback string) string { return fallback }
+
+// SetTranslator replaces the translator used by DescribeScope. A nil t
+// restores the default, which always returns the English text
+func SetTranslator(t Translator) {
+	if t == nil {
+		t = untranslated
+	}
+	translator.store(t)
+}
+
+// DescribeScope returns the description of scope in language lang
+func DescribeScope(scope, lang string) (*ScopeInfo, error) {
+	return CurrentPolicy().describeScope(scope, lang)
+}
+
+func (p *Policy) describeScope(scope, lang string) (*ScopeInfo, error) {
+	def, ok := p.defs[scope]
+	if !ok {
+		return nil, fmt.Errorf("undefined scope %q", scope)
+	}
+
+	t := translator.load().(Translator)
+	set := p.scopes[scope]
+	info := &ScopeInfo{
+		Name:        scope,
+		Title:       t(lang, "scope."+scope+".title", def.Title),
+		Description: t(lang, "scope."+scope+".description", def.Description),
+		Sensitivity: def.Sensitivity.String(),
+		Parents:     append([]string(nil), def.Parents...),
+		Resources:   make([]string, 0, len(set)),
+	}
+	for _, r := range set.Resources() {
+		info.Resources = append(info.Resources, r+":"+set.Actions(r))
+	}
+	return info, nil
+}
+
+// DescribeScopes returns the description of every scope in language lang,
+// ordered by name
+func DescribeScopes(lang string) []*ScopeInfo {
+	p := CurrentPolicy()
+	names := p.ScopeNames()
+	out := make([]*ScopeInfo, 0, len(names))
+	for _, name := range names {
+		info, _ := p.describeScope(name, lang)
+		out = append(out, info)
+	}
+	return out
+}
@@ -0,0 +1,267 @@
+package perm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// scopeLetters are the actions of a scope entry, in canonical order: w
+// (write), r (read), e (export), p. The n-th letter is the n-th bit of the
+// actions of a resource in a ScopeSet
+const scopeLetters = "wrep"
+
+// ScopeSet is a parsed set of scope entries, it maps a resource, e.g.
+// conversation or other_conversation, to its actions. Resources without any
+// action are never stored.
+//
+// A resource may also be a pattern: whitelist_* covers every resource
+// starting with whitelist_, * covers every resource. Dotted names are
+// sub-resources, conversation covers conversation.message. Grants only add
+// actions, so the outcome never depends on the order of the entries; Match
+// tells which entry is the most specific for a resource
+type ScopeSet map[string]uint8
+
+// ParseScopeSet parses entries such as "conversation:rw tag:r", separated by
+// spaces, commas, semicolons or newlines. Entries of the same resource are
+// joined. Malformed entries and unknown actions are ignored
+func ParseScopeSet(s string) ScopeSet {
+	set := ScopeSet{}
+	for _, item := range scopeItems(s) {
+		split := strings.Split(strings.TrimSpace(item), ":")
+		if len(split) != 2 {
+			continue
+		}
+		set.add(split[0], scopeBits(split[1]))
+	}
+	return set
+}
+
+// ParseScopeSetStrict is ParseScopeSet which fails on malformed entries and
+// unknown actions instead of ignoring them
+func ParseScopeSetStrict(s string) (ScopeSet, error) {
+	set := ScopeSet{}
+	for _, item := range scopeItems(s) {
+		split := strings.Split(strings.TrimSpace(item), ":")
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("malformed entry %q, expect resource:actions", item)
+		}
+
+		if !validScopeResource(split[0]) {
+			return nil, fmt.Errorf("malformed resource %q in %q", split[0], item)
+		}
+
+		for _, a := range split[1] {
+			if !strings.ContainsRune(scopeLetters, a) {
+				return nil, fmt.Errorf("unknown action %q in %q", a, item)
+			}
+		}
+		set.add(split[0], scopeBits(split[1]))
+	}
+	return set, nil
+}
+
+// validScopeResource reports whether resource is made of dot separated
+// names, with an optional * at the end
+func validScopeResource(resource string) bool {
+	name := strings.TrimSuffix(resource, "*")
+	if name == "" {
+		return resource == "*"
+	}
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		// conversation.* ends with an empty part
+		if part == "" && (i < len(parts)-1 || !strings.HasSuffix(resource, ".*")) {
+			return false
+		}
+
+		for _, c := range part {
+			if c != '_' && (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// covers reports whether grant, a resource or a pattern, covers resource,
+// which may be a pattern itself
+func covers(grant, resource string) bool {
+	if strings.HasSuffix(grant, "*") {
+		return strings.HasPrefix(strings.TrimSuffix(resource, "*"), strings.TrimSuffix(grant, "*"))
+	}
+
+	if strings.HasSuffix(resource, "*") {
+		return strings.HasPrefix(strings.TrimSuffix(resource, "*"), grant+".")
+	}
+	return resource == grant || strings.HasPrefix(resource, grant+".")
+}
+
+func scopeItems(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ';' || r == ',' || r == '\n'
+	})
+}
+
+// scopeBits converts action letters to the bits of a ScopeSet
+func scopeBits(actions string) uint8 {
+	out := uint8(0)
+	for i, l := range scopeLetters {
+		if strings.ContainsRune(actions, l) {
+			out |= 1 << uint(i)
+		}
+	}
+	return out
+}
+
+func (s ScopeSet) add(resource string, bits uint8) {
+	if bits != 0 {
+		s[resource] |= bits
+	}
+}
+
+// Actions returns the action letters of the entry resource in canonical
+// order, see Allowed for the actions the set allows on a resource
+func (s ScopeSet) Actions(resource string) string {
+	return bitsToLetters(s[resource])
+}
+
+// Allowed returns the action letters the set allows on resource, counting
+// the patterns and parents which cover it
+func (s ScopeSet) Allowed(resource string) string {
+	return bitsToLetters(s.covering(resource))
+}
+
+// covering returns the actions of the entries covering resource
+func (s ScopeSet) covering(resource string) uint8 {
+	out := uint8(0)
+	for grant, bits := range s {
+		if covers(grant, resource) {
+			out |= bits
+		}
+	}
+	return out
+}
+
+// Match returns the most specific entry which allows action on resource,
+// or "" if none does. An exact entry comes first, then the closest parent,
+// then the longest pattern, * is the last resort
+func (s ScopeSet) Match(resource string, action rune) string {
+	bit := scopeBits(string(action))
+	best := ""
+	for grant, bits := range s {
+		if bits&bit == 0 || !covers(grant, resource) {
+			continue
+		}
+
+		if best == "" || moreSpecific(grant, best) {
+			best = grant
+		}
+	}
+	return best
+}
+
+// moreSpecific orders entries: non-patterns first, then longer ones. Equal
+// length entries are ordered by name so the choice is deterministic
+func moreSpecific(a, b string) bool {
+	wa, wb := strings.HasSuffix(a, "*"), strings.HasSuffix(b, "*")
+	if wa != wb {
+		return wb
+	}
+
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a < b
+}
+
+func bitsToLetters(bits uint8) string {
+	out := ""
+	for i, l := range scopeLetters {
+		if bits&(1<<uint(i)) != 0 {
+			out += string(l)
+		}
+	}
+	return out
+}
+
+// Resources returns the resources of the set in ascending order
+func (s ScopeSet) Resources() []string {
+	out := make([]string, 0, len(s))
+	for r := range s {
+		out = append(out, r)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Pairs returns every resource:action of the set, one action each, in
+// canonical order, e.g. [conversation:w conversation:r tag:r]
+func (s ScopeSet) Pairs() []string {
+	out := make([]string, 0, len(s))
+	for _, r := range s.Resources() {
+		for _, a := range s.Actions(r) {
+			out = append(out, r+":"+string(a))
+		}
+	}
+	return out
+}
+
+// Union returns a new set with the actions of both s and o
+func (s ScopeSet) Union(o ScopeSet) ScopeSet {
+	out := make(ScopeSet, len(s))
+	for r, bits := range s {
+		out.add(r, bits)
+	}
+	for r, bits := range o {
+		out.add(r, bits)
+	}
+	return out
+}
+
+// Intersect returns a new set with the actions allowed by both s and o
+func (s ScopeSet) Intersect(o ScopeSet) ScopeSet {
+	out := ScopeSet{}
+	for r, bits := range s {
+		out.add(r, bits&o.covering(r))
+	}
+	for r, bits := range o {
+		out.add(r, bits&s.covering(r))
+	}
+	return out
+}
+
+// Difference returns a new set with the actions of s which o does not allow
+func (s ScopeSet) Difference(o ScopeSet) ScopeSet {
+	out := ScopeSet{}
+	for r, bits := range s {
+		out.add(r, bits&^o.covering(r))
+	}
+	return out
+}
+
+// SubsetOf reports whether o allows every action of s
+func (s ScopeSet) SubsetOf(o ScopeSet) bool {
+	for r, bits := range s {
+		if bits&^o.covering(r) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether s and o have the same actions
+func (s ScopeSet) Equal(o ScopeSet) bool {
+	return s.SubsetOf(o) && o.SubsetOf(s)
+}
+
+// String returns the canonical form of the set, resources in ascending
+// order, e.g.: conversation:wr tag:r
+func (s ScopeSet) String() string {
+	out := make([]string, 0, len(s))
+	for _, r := range s.Resources() {
+		out = append(out, r+":"+s.Actions(r))
+	}
+	return strings.Join(out, " ")
+}